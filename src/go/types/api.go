@@ -28,6 +28,7 @@ package types
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/constant"
@@ -52,6 +53,8 @@ type Error struct {
 	go116code  errorCode
 	go116start token.Pos
 	go116end   token.Pos
+
+	types []Type // operand types involved in the error, if any
 }
 
 // Error returns an error string formatted as follows:
@@ -60,6 +63,24 @@ func (err Error) Error() string {
 	return fmt.Sprintf("%s: %s", err.Fset.Position(err.Pos), err.Msg)
 }
 
+// Code returns a stable identifier for the kind of error, such as
+// "MismatchedTypes". The result is the empty string if the error
+// has no associated code.
+func (err Error) Code() string {
+	if c := int(err.go116code); c > 0 && c < len(errorCodeNames) {
+		return errorCodeNames[c]
+	}
+	return ""
+}
+
+// Types returns the types of the operands involved in the error,
+// in source order, if they were recorded; otherwise the result is nil.
+// Currently, operand types are recorded for invalid binary operations
+// and comparisons only.
+func (err Error) Types() []Type {
+	return err.types
+}
+
 // An Importer resolves import paths to Packages.
 //
 // CAUTION: This interface does not support the import of locally
@@ -154,6 +175,161 @@ type Config struct {
 	// If DisableUnusedImportCheck is set, packages are not checked
 	// for unused imports.
 	DisableUnusedImportCheck bool
+
+	// If ReportAllSliceIndexErrors is set, every pair of swapped
+	// constant indices in a slice expression is reported (e.g., all
+	// three pairs in a[2:1:0]). Otherwise, only the first pair found
+	// is reported.
+	ReportAllSliceIndexErrors bool
+
+	// If NoStringConstFold is set, the concatenation of constant strings
+	// is not folded into a constant but treated as a (non-constant) value
+	// computed at run time. As a consequence, no constant value is recorded
+	// in Info.Types for such expressions, and they cannot be used where a
+	// constant is required. The concatenation of untyped constant strings
+	// is of type string (and so are its operands), like the concatenation
+	// of string variables.
+	NoStringConstFold bool
+
+	// If OverflowMessageSuffix is not empty, it is appended (separated
+	// by a blank) to the error messages reported for constants that
+	// overflow or are truncated when converted to a numeric type, for
+	// instance to identify the target platform whose Sizes were used:
+	// "x (untyped int constant 1099511627776) overflows int (32-bit target)".
+	// The suffix must not contain formatting verbs.
+	OverflowMessageSuffix string
+
+	// If OnCompositeLit != nil, it is called after each valid composite
+	// literal (of struct, array, slice, or map type) has been checked,
+	// with the literal's type and its number of elements. Clients may use
+	// it to enforce additional rules (for instance, that an array literal
+	// specifies all elements); the type checker does not interpret it.
+	OnCompositeLit func(lit *ast.CompositeLit, typ Type, elemCount int)
+
+	// If AllowIntAsBool is set, the untyped integer constants 0 and 1
+	// may be used where a boolean value is expected (for instance, in
+	// var b bool = 1, or as the condition of an if or for statement);
+	// they denote false and true, respectively. This is not permitted
+	// by the Go specification.
+	AllowIntAsBool bool
+
+	// MaxUntypedConstBits is the maximum size, in bits, of untyped integer
+	// constant values; operations producing larger values are reported as
+	// constant overflows. If MaxUntypedConstBits is 0, a limit of 512 bits
+	// is used. Values below 64 are invalid and cause Check to fail without
+	// type-checking any file.
+	MaxUntypedConstBits int
+
+	// If ExprVisitor != nil, it is called for each expression with the
+	// type and value recorded for it; i.e., it is called exactly for the
+	// expressions that are recorded in Info.Types, whether or not Info.Types
+	// is present. For untyped expressions it is called when their final type
+	// is known, possibly at the end of type checking; intermediate untyped
+	// types are not reported. Similarly, built-in functions are reported only
+	// for valid calls, with their call-specific signature, or with the invalid
+	// type if the call has a constant value, as in len("abc").
+	ExprVisitor func(ast.Expr, TypeAndValue)
+
+	// If ForbidPositionalUnexported is set, struct literals without keys
+	// may not assign to unexported fields even if the struct type is declared
+	// in the package being checked; such assignments are reported as (soft)
+	// errors. Struct literals of types declared in other packages can never
+	// assign to unexported fields.
+	ForbidPositionalUnexported bool
+
+	// If TraceHint != nil, it is called for each element e of an array,
+	// slice, or map composite literal, and for each map literal key, with
+	// the type hint used to check e (the element or key type). If e is
+	// itself a composite literal with elided type (as in []T{{1, 2}}),
+	// hint is the type of e.
+	TraceHint func(e ast.Expr, hint Type)
+
+	// If AllowPartialPositionalStructLit is set, struct literals without
+	// keys may omit trailing fields, which are then zero, as in keyed
+	// literals. The number of omitted fields is recorded in
+	// Info.PartialStructLits. Too many values remain an error.
+	AllowPartialPositionalStructLit bool
+
+	// MaxShiftCount is the maximum shift count permitted in constant
+	// shift expressions. If MaxShiftCount is 0, the maximum is 1074
+	// (1023 - 1 + 52), which permits expressing the smallest positive
+	// float64 value as 1.0 / (1 << 1074). Note that the result of a
+	// constant shift must also not exceed the untyped constant size
+	// (see MaxUntypedConstBits).
+	MaxShiftCount uint
+
+	// If ConstFoldBudgetBits > 0, it limits the accumulated size, in bits,
+	// of all integer constant values computed by constant operations
+	// (such as 1<<100 or x*y) in a single package-level declaration or
+	// function body. Once the limit is exceeded, an error is reported and
+	// the results of further operations are unknown. The budget protects
+	// against pathological input; it is not part of the Go specification.
+	ConstFoldBudgetBits int
+
+	// If Suggestions is set, some error messages include a suggestion
+	// on how to fix the error, such as "use math.Mod" for the operator
+	// % applied to floating-point operands.
+	Suggestions bool
+
+	// If OnDuplicateLitKey != nil, it is called for each duplicate constant
+	// key in a map literal and each duplicate index in an array or slice
+	// literal, in addition to reporting the error. For map literals, key is
+	// the duplicate key expression; for array and slice literals, key is the
+	// index expression, or the element itself if it has no explicit index.
+	// firstPos is the position of the first element with the same key or
+	// index, and dupPos the position of the duplicate element.
+	OnDuplicateLitKey func(key ast.Expr, firstPos, dupPos token.Pos)
+
+	// If TraceTypeUpdate != nil, it is called whenever the type of an
+	// untyped expression e is updated from old to new because the context
+	// in which it is used determines its type (for instance, when 1 << s
+	// is assigned to a variable of type int64). If final is set, new is
+	// the final type of e. The operands of e (if any) are updated before e.
+	// The operands of constant expressions, which keep their untyped type
+	// (see Info.NeverMaterialized), are not reported.
+	TraceTypeUpdate func(e ast.Expr, old, new Type, final bool)
+
+	// If StrictShiftOperands is set, the left operand of a shift must not be
+	// an untyped floating-point (or complex) constant, even if its value is
+	// an integer as in 2.0 << 1, which is permitted by the spec.
+	StrictShiftOperands bool
+
+	// If OnOverflowCheck != nil, it is called for each constant operation
+	// result e of type t that is checked for overflow: typed constants are
+	// checked for representability in their type, and untyped integer
+	// constants against the maximum untyped constant precision (see
+	// MaxUntypedConstBits). Results that cannot be represented at all are
+	// reported as overflowed as well. The overflowed argument reports
+	// whether the check failed, in which case an error is reported, too.
+	OnOverflowCheck func(e ast.Expr, t Type, overflowed bool)
+
+	// If AllowUnexportedFieldLit != nil, it is called for each unexported
+	// field of a struct type declared in another package that is implicitly
+	// assigned to in a struct literal of type litType without keys. If it
+	// returns true, the assignment is permitted; otherwise it is reported as
+	// an error, as is the default.
+	AllowUnexportedFieldLit func(field *Var, litType Type) bool
+
+	// If OnSizeof != nil, it is called with the type t and its size
+	// whenever the type checker determines the size of a type (using
+	// Sizes, if set), such as the size of int when checking whether a
+	// constant is representable as an int, or the argument type of
+	// unsafe.Sizeof.
+	OnSizeof func(t Type, size int64)
+
+	// If RecoverCompositeLitType is set, the elements of a composite literal
+	// with missing type (and no type implied by the context) are still
+	// type-checked as far as possible, so that the identifiers they contain
+	// are recorded as used. No errors other than the missing type are
+	// reported for nested composite literals with omitted type.
+	RecoverCompositeLitType bool
+
+	// If ReportSwappedOffsetIndices is set, slice expressions whose
+	// non-constant indices are the same variable plus constant offsets
+	// that are out of order, as in s[i+1:i], are reported as soft errors
+	// with code SwappedSliceIndices. Such slice expressions are valid but
+	// always panic at run time (unless the offsets overflow).
+	ReportSwappedOffsetIndices bool
 }
 
 func srcimporter_setUsesCgo(conf *Config) {
@@ -177,6 +353,20 @@ func (info *Info) TypeOf(e ast.Expr) Type {
 	return nil
 }
 
+// DefaultType returns the default type of the untyped type recorded for
+// expression e in the Untyped map (see Default), and reports whether e was
+// untyped. For example, the default type of the constant 1.0 in x := 1.0
+// is float64. The default type of the untyped nil is untyped nil. If e was
+// typed when checked, or e is not found, the result is (nil, false).
+// Precondition: the Untyped map is populated.
+//
+func (info *Info) DefaultType(e ast.Expr) (Type, bool) {
+	if t := info.Untyped[e]; t != nil {
+		return Default(t), true
+	}
+	return nil, false
+}
+
 // ObjectOf returns the object denoted by the specified id,
 // or nil if not found.
 //
@@ -195,9 +385,10 @@ func (info *Info) ObjectOf(id *ast.Ident) Object {
 // TypeAndValue reports the type and value (for constants)
 // of the corresponding expression.
 type TypeAndValue struct {
-	mode  operandMode
-	Type  Type
-	Value constant.Value
+	mode       operandMode
+	Type       Type
+	Value      constant.Value
+	viaPointer bool
 }
 
 // IsVoid reports whether the corresponding expression
@@ -240,6 +431,16 @@ func (tv TypeAndValue) Addressable() bool {
 	return tv.mode == variable
 }
 
+// IndexViaPointer reports whether the corresponding expression is a
+// (possibly parenthesized) index expression p[i] where p is a pointer
+// to an array, as in (&a)[i]. Such an expression is addressable since
+// it denotes an element of the array pointed to by p, independent of
+// whether the array is addressable. It is only reported for entries
+// of Info.Types.
+func (tv TypeAndValue) IndexViaPointer() bool {
+	return tv.viaPointer
+}
+
 // Assignable reports whether the corresponding expression
 // is assignable to (provided a value of the right type).
 func (tv TypeAndValue) Assignable() bool {
@@ -252,6 +453,45 @@ func (tv TypeAndValue) HasOk() bool {
 	return tv.mode == commaok || tv.mode == mapindex
 }
 
+// An OperandMode describes the (addressing) mode of an expression, as
+// determined by the type checker.
+type OperandMode byte
+
+// The operand modes. They distinguish, for instance, addressable variables
+// such as the slice index expression s[i] from map index expressions m[k],
+// which may also be assigned to but are not addressable.
+const (
+	InvalidMode  = OperandMode(invalid)   // invalid expression
+	NoValueMode  = OperandMode(novalue)   // call of a function without results
+	BuiltinMode  = OperandMode(builtin)   // built-in function
+	TypeMode     = OperandMode(typexpr)   // type
+	ConstantMode = OperandMode(constant_) // constant
+	VariableMode = OperandMode(variable)  // addressable variable
+	MapIndexMode = OperandMode(mapindex)  // map index expression
+	ValueMode    = OperandMode(value)     // computed value
+	CommaOkMode  = OperandMode(commaok)   // value that may be used in a comma-ok expression
+	CommaErrMode = OperandMode(commaerr)  // like CommaOkMode, but the second value is an error
+	CgoFuncMode  = OperandMode(cgofunc)   // cgo function
+)
+
+func (m OperandMode) String() string {
+	if int(m) < len(operandModeString) {
+		return operandModeString[m]
+	}
+	return fmt.Sprintf("OperandMode(%d)", m)
+}
+
+// Mode returns the operand mode of the corresponding expression.
+func (tv TypeAndValue) Mode() OperandMode {
+	return OperandMode(tv.mode)
+}
+
+// A UnaryOp describes a unary operation.
+type UnaryOp struct {
+	Op         token.Token // operator; token.MUL for pointer indirections
+	ResultMode string      // "constant", "variable", "value", or "commaok"
+}
+
 // _Inferred reports the _Inferred type arguments and signature
 // for a parameterized function call that uses type inference.
 type _Inferred struct {
@@ -309,12 +549,405 @@ func AssignableTo(V, T Type) bool {
 	return ok
 }
 
+// AssignableToReason is like AssignableTo but also returns a description
+// of why a value of type V is not assignable to a variable of type T, of
+// the form "V is not assignable to T: reason". If the value is assignable,
+// the description is empty.
+func AssignableToReason(V, T Type) (bool, string) {
+	x := operand{mode: value, typ: V}
+	var reason string
+	ok, code := x.assignableTo(nil, T, &reason) // check not needed for non-constant x
+	if ok {
+		return true, ""
+	}
+	if reason == "" {
+		reason = assignabilityReason(V, T, code)
+	}
+	return false, fmt.Sprintf("%s is not assignable to %s: %s", V, T, reason)
+}
+
+// assignabilityReason describes why V is not assignable to T,
+// given the error code reported by operand.assignableTo.
+func assignabilityReason(V, T Type, code errorCode) string {
+	Vu := optype(V)
+	Tu := optype(T)
+	if code == _InvalidChanAssign || isNamed(V) && isNamed(T) && Identical(Vu, Tu) {
+		return "differing named types"
+	}
+	if isUntyped(Vu) {
+		return "untyped value cannot be represented"
+	}
+	var velem, telem Type
+	switch v := Vu.(type) {
+	case *Chan:
+		if t, _ := Tu.(*Chan); t != nil {
+			if Identical(v.elem, t.elem) {
+				return "differing channel directions"
+			}
+			velem, telem = v.elem, t.elem
+		}
+	case *Pointer:
+		if t, _ := Tu.(*Pointer); t != nil {
+			velem, telem = v.base, t.base
+		}
+	case *Slice:
+		if t, _ := Tu.(*Slice); t != nil {
+			velem, telem = v.elem, t.elem
+		}
+	case *Array:
+		if t, _ := Tu.(*Array); t != nil {
+			if v.len != t.len {
+				return "differing array lengths"
+			}
+			velem, telem = v.elem, t.elem
+		}
+	case *Map:
+		if t, _ := Tu.(*Map); t != nil {
+			if !Identical(v.key, t.key) {
+				return "differing key types"
+			}
+			velem, telem = v.elem, t.elem
+		}
+	}
+	if velem != nil && !Identical(velem, telem) {
+		return "differing element types"
+	}
+	return "incompatible types"
+}
+
+// An Assignability describes whether, and by which rule, a value of
+// one type is assignable to a variable of another type.
+type Assignability struct {
+	OK   bool   // the assignment is valid
+	Kind string // the assignability rule that applies; empty if !OK
+}
+
+// AssignabilityDetail reports whether a value of type V is assignable to
+// a variable of type T, like AssignableTo, and which rule of the spec
+// makes it so. The Kind of a valid assignment is one of:
+//
+//     "identical"             V and T are identical
+//     "nil"                   V is untyped nil and T has nil values
+//     "untyped-convert"       V is untyped and representable by T
+//     "interface-box"         T is an interface type implemented by V
+//     "directional-channel"   V is a bidirectional channel with T's element type
+//     "unnamed-underlying"    V and T have identical underlying types
+//                             and at least one of them is not named
+//
+func AssignabilityDetail(V, T Type) Assignability {
+	x := operand{mode: value, typ: V}
+	if ok, _ := x.assignableTo(nil, T, nil); !ok {
+		return Assignability{}
+	}
+	var kind string
+	Vu := optype(V)
+	Tu := optype(T)
+	switch {
+	case Identical(V, T):
+		kind = "identical"
+	case isUntyped(Vu):
+		// see implicitTypeAndValue
+		switch {
+		case x.isNil():
+			kind = "nil"
+		case IsInterface(Tu):
+			kind = "interface-box"
+		default:
+			kind = "untyped-convert"
+		}
+	case Identical(Vu, Tu):
+		kind = "unnamed-underlying"
+	case IsInterface(Tu):
+		kind = "interface-box"
+	default:
+		// the only remaining case are bidirectional channels
+		kind = "directional-channel"
+	}
+	return Assignability{true, kind}
+}
+
+// IndexResultMode reports the mode of an index expression x[i] where x
+// is of type baseType; baseAddressable reports whether x is addressable.
+// The result mode is one of:
+//
+//	"variable"  for arrays if x is addressable, pointers to arrays
+//	            (independent of baseAddressable), and slices
+//	"value"     for arrays if x is not addressable, and strings
+//	"mapindex"  for maps (the result may be used in a comma-ok
+//	            assignment but is not addressable)
+//
+// If x cannot be indexed, the result is ("", false). Type parameters are
+// not handled and also yield ("", false).
+func IndexResultMode(baseType Type, baseAddressable bool) (mode string, ok bool) {
+	switch t := optype(baseType).(type) {
+	case *Basic:
+		if isString(t) {
+			return "value", true
+		}
+	case *Array:
+		if baseAddressable {
+			return "variable", true
+		}
+		return "value", true
+	case *Pointer:
+		if asArray(t.base) != nil {
+			return "variable", true
+		}
+	case *Slice:
+		return "variable", true
+	case *Map:
+		return "mapindex", true
+	}
+	return "", false
+}
+
+// DefaultChangesValue reports whether the constant value v of the untyped
+// kind untypedKind changes when it is converted to the default type for
+// that kind (see Default). Only floating-point and complex constants may be
+// rounded (e.g., 0.1 is not exactly representable as a float64); boolean,
+// string, and integer constants keep their value. The result is also true
+// if v is not representable by the default type at all, as for an integer
+// constant that overflows int. The size of int is assumed to be 64 bits.
+// For typed kinds, DefaultChangesValue reports whether converting v to the
+// respective type changes its value.
+func DefaultChangesValue(v constant.Value, untypedKind BasicKind) bool {
+	if untypedKind < 0 || int(untypedKind) >= len(Typ) || untypedKind == UntypedNil || untypedKind == Invalid {
+		return false
+	}
+	typ, _ := Default(Typ[untypedKind]).(*Basic)
+	if typ == nil {
+		return false
+	}
+	var rounded constant.Value
+	if !representableConst(v, sizesChecker(nil), typ, &rounded) {
+		return true
+	}
+	return rounded != nil && !constant.Compare(v, token.EQL, rounded)
+}
+
+// sizesChecker returns a Checker for the evaluation of constants and
+// operands outside of a package (e.g., to test if a constant is
+// representable by a type) with the given sizes. If sizes is nil,
+// the sizes are the ones of SizesFor("gc", "amd64").
+func sizesChecker(sizes Sizes) *Checker {
+	return NewChecker(&Config{Sizes: sizes}, nil, nil, nil)
+}
+
+// IndexMayPanic reports whether the index expression e may panic at run
+// time because its index is out of bounds. It returns false only if info
+// records e in Info.StaticInBoundsIndex, i.e., if e indexes an array, a
+// pointer to an array, or a constant string with a constant index that is
+// known to be in bounds. Otherwise, including when info or its
+// StaticInBoundsIndex map is nil, it conservatively returns true.
+// (Note that indexing through a nil pointer to an array panics even if
+// the index is in bounds.)
+func IndexMayPanic(info *Info, e *ast.IndexExpr) bool {
+	return info == nil || !info.StaticInBoundsIndex[e]
+}
+
+// CommaOkType returns the type of the (first) value of the expression e,
+// as recorded in info.Types, and whether e may be used in a comma-ok
+// assignment (a map index expression, type assertion, or channel receive),
+// in which case the second value is of type bool. If e is used in a comma-ok
+// assignment already, the result is the type of the first value of the
+// recorded tuple type. If there is no type recorded for e, the result is
+// (nil, false).
+func CommaOkType(e ast.Expr, info *Info) (firstType Type, hasOk bool) {
+	if info == nil {
+		return nil, false
+	}
+	tv, ok := info.Types[e]
+	if !ok || tv.Type == nil {
+		return nil, false
+	}
+	firstType = tv.Type
+	if !tv.HasOk() {
+		return firstType, false
+	}
+	if t, _ := firstType.(*Tuple); t != nil && t.Len() == 2 {
+		firstType = t.At(0).Type()
+	}
+	return firstType, true
+}
+
+// RepresentableAll reports, for each constant vals[i], whether it is
+// representable by a value of the basic type types[i]. The result has
+// one entry per constant: nil if the constant is representable, and an
+// error describing the problem (in the same way the type checker does)
+// otherwise. Unknown constant values are considered representable.
+// If sizes is nil, the sizes of int, uint, and uintptr are the ones of
+// SizesFor("gc", "amd64"). The lengths of vals and types must match.
+func RepresentableAll(vals []constant.Value, types []*Basic, sizes Sizes) []error {
+	if len(vals) != len(types) {
+		panic("RepresentableAll: mismatched number of values and types")
+	}
+	check := sizesChecker(sizes)
+	errs := make([]error, len(vals))
+	for i, val := range vals {
+		var typ *Basic
+		switch val.Kind() {
+		case constant.Bool:
+			typ = Typ[UntypedBool]
+		case constant.String:
+			typ = Typ[UntypedString]
+		case constant.Int:
+			typ = Typ[UntypedInt]
+		case constant.Float:
+			typ = Typ[UntypedFloat]
+		case constant.Complex:
+			typ = Typ[UntypedComplex]
+		default:
+			continue
+		}
+		x := operand{mode: constant_, typ: typ, val: val}
+		if _, code := check.representation(&x, types[i]); code != 0 {
+			errs[i] = conversionError(code, val, types[i])
+		}
+	}
+	return errs
+}
+
+// conversionError returns the error for the failed conversion (with
+// the given non-zero error code) of the constant val to type typ.
+func conversionError(code errorCode, val constant.Value, typ Type) error {
+	switch code {
+	case _TruncatedFloat:
+		msg := fmt.Sprintf("%s truncated to %s", val, typ)
+		if t := truncatedInt(val); t != nil {
+			msg += fmt.Sprintf(" (would be %s)", t)
+		}
+		return errors.New(msg)
+	case _NumericOverflow:
+		return fmt.Errorf("%s overflows %s", val, typ)
+	}
+	return fmt.Errorf("cannot convert %s to %s", val, typ)
+}
+
+// ConvertUntyped returns the value and type of the untyped constant val of
+// kind fromKind when it is implicitly converted to the target type, as in an
+// assignment: for instance, an untyped integer constant converted to uint8
+// must be representable as a uint8, and an untyped constant converted to an
+// interface type has its default type. The value may change when it is
+// converted; for instance, floating-point values are rounded to the precision
+// of the target type. If the conversion is not possible, the result is an
+// error. fromKind must be an untyped kind matching the kind of val; for
+// UntypedNil, val must be nil.
+// If sizes is nil, the sizes of int, uint, and uintptr are the ones of
+// SizesFor("gc", "amd64").
+func ConvertUntyped(val constant.Value, fromKind BasicKind, target Type, sizes Sizes) (constant.Value, Type, error) {
+	if fromKind < 0 || int(fromKind) >= len(Typ) || !isUntyped(Typ[fromKind]) {
+		return nil, nil, fmt.Errorf("ConvertUntyped: %d is not an untyped kind", fromKind)
+	}
+	if !untypedValueOk(fromKind, val) {
+		return nil, nil, fmt.Errorf("ConvertUntyped: value kind of %v does not match %s", val, Typ[fromKind])
+	}
+	x := operand{mode: constant_, typ: Typ[fromKind], val: val}
+	if fromKind == UntypedNil {
+		x.mode = value
+	}
+	check := sizesChecker(sizes)
+	typ, v, code := check.implicitTypeAndValue(&x, target)
+	if code != 0 {
+		if x.mode != constant_ {
+			return nil, nil, fmt.Errorf("cannot convert %s to %s", x.typ, target)
+		}
+		return nil, nil, conversionError(code, val, target)
+	}
+	if v == nil {
+		v = val
+	}
+	return v, typ, nil
+}
+
+// untypedValueOk reports whether val may be the value of an untyped constant
+// (or nil) of kind k. Integer values are accepted for all numeric kinds, and
+// floating-point values for untyped complex constants.
+func untypedValueOk(k BasicKind, val constant.Value) bool {
+	if k == UntypedNil || val == nil {
+		return k == UntypedNil && val == nil
+	}
+	switch val.Kind() {
+	case constant.Unknown:
+		return true
+	case constant.Bool:
+		return k == UntypedBool
+	case constant.String:
+		return k == UntypedString
+	case constant.Int:
+		return k == UntypedInt || k == UntypedRune || k == UntypedFloat || k == UntypedComplex
+	case constant.Float:
+		return k == UntypedFloat || k == UntypedComplex
+	case constant.Complex:
+		return k == UntypedComplex
+	}
+	return false
+}
+
 // ConvertibleTo reports whether a value of type V is convertible to a value of type T.
 func ConvertibleTo(V, T Type) bool {
 	x := operand{mode: value, typ: V}
 	return x.convertibleTo(nil, T, nil) // check not needed for non-constant x
 }
 
+// AssignableToSized is like AssignableTo but also accepts the value val of
+// a constant of type V (val is nil for non-constant values). Whether the
+// constant is representable as a value of type T is checked using the
+// given sizes, which matters for the types int, uint, and uintptr. If sizes
+// is nil, the sizes are the ones of SizesFor("gc", "amd64").
+func AssignableToSized(V, T Type, val constant.Value, sizes Sizes) bool {
+	check := sizesChecker(sizes)
+	ok, _ := sizedOperand(V, val).assignableTo(check, T, nil)
+	return ok
+}
+
+// ConvertibleToSized is like ConvertibleTo but also accepts the value val
+// of a constant of type V (val is nil for non-constant values). Whether the
+// constant is representable as a value of type T is checked using the
+// given sizes, which matters for the types int, uint, and uintptr. If sizes
+// is nil, the sizes are the ones of SizesFor("gc", "amd64").
+func ConvertibleToSized(V, T Type, val constant.Value, sizes Sizes) bool {
+	check := sizesChecker(sizes)
+	return check.convertibleToType(sizedOperand(V, val), T)
+}
+
+// sizedOperand returns a constant operand with type V and value val,
+// or a value operand of type V if val is nil.
+func sizedOperand(V Type, val constant.Value) *operand {
+	if val == nil {
+		return &operand{mode: value, typ: V}
+	}
+	return &operand{mode: constant_, typ: V, val: val}
+}
+
+// BinaryOpValid reports whether the binary operation x op y is valid for
+// (non-constant) operands of types x and y, as determined by the type checker.
+// If the operation is invalid, the result includes the reason, which is the
+// error the type checker reports for the expression x op y where x and y are
+// variables of the respective types. Untyped operands are first converted to
+// the type of the other operand, as in an expression. For shifts, the left
+// operand must be of integer type, and the right operand must be of integer
+// type or untyped numeric. For comparisons, one operand must be assignable to
+// the type of the other, and the operands must be comparable (for == and !=)
+// or ordered (for <, <=, >, and >=); comparisons with nil are valid if the
+// other operand may be nil. Interfaces must be complete. If x or y is the
+// invalid type, the operation is invalid with the reason "invalid operand type".
+func BinaryOpValid(op token.Token, x, y Type) (valid bool, reason string) {
+	if x == Typ[Invalid] || y == Typ[Invalid] {
+		// the type checker doesn't report errors for invalid operands
+		return false, "invalid operand type"
+	}
+	conf := Config{Error: func(err error) {
+		if reason == "" {
+			reason = err.(Error).Msg
+		}
+	}}
+	check := NewChecker(&conf, token.NewFileSet(), nil, nil)
+	xo := operand{mode: value, expr: ast.NewIdent("x"), typ: x}
+	yo := operand{mode: value, expr: ast.NewIdent("y"), typ: y}
+	check.binaryOperands(&xo, &yo, nil, op, token.NoPos)
+	return xo.mode != invalid, reason
+}
+
 // Implements reports whether type V implements interface T.
 func Implements(V Type, T *Interface) bool {
 	f, _ := MissingMethod(V, T, true)
@@ -323,6 +956,10 @@ func Implements(V Type, T *Interface) bool {
 
 // Identical reports whether x and y are identical types.
 // Receivers of Signature types are ignored.
+//
+// Identical is the relation used by the type checker, for instance to
+// determine if the operands of a binary operation have matching types.
+// Interfaces must be complete (see Interface.Complete).
 func Identical(x, y Type) bool {
 	return (*Checker)(nil).identical(x, y)
 }
@@ -7,7 +7,11 @@
 
 package types
 
-import "go/ast"
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+)
 
 // Info holds result type information for a type-checked package.
 // Only the information for which a map is provided is collected.
@@ -97,6 +101,263 @@ type Info struct {
 	// in source order. Variables without an initialization expression do not
 	// appear in this list.
 	InitOrder []*Initializer
+
+	// ClampedOverflows records untyped constant expressions whose value
+	// grew too large and was replaced by an unknown value after reporting
+	// a "constant overflow" error. It distinguishes such expressions from
+	// ones whose value is genuinely unknown.
+	ClampedOverflows map[ast.Expr]bool
+
+	// LitElemCount maps composite literals to the number of elements
+	// checked by the type checker.
+	LitElemCount map[*ast.CompositeLit]int
+
+	// StringByteIndex records index expressions s[i] where s is a string.
+	// Such expressions yield a byte, not a rune; tools may use this to
+	// suggest []rune(s)[i] or a range loop instead.
+	StringByteIndex map[*ast.IndexExpr]bool
+
+	// PendingShiftResults records non-constant shift expressions with an
+	// untyped constant lhs operand, such as 1 << s. The type of such a shift
+	// is determined by its context and may not be known until the enclosing
+	// expression or assignment is checked; intermediate (untyped) types seen
+	// for these expressions should not be trusted. The final type is recorded
+	// in Types.
+	PendingShiftResults map[ast.Expr]bool
+
+	// LitMaxIndex maps array and slice composite literals to their length
+	// as determined by the literal elements, i.e., the maximum (explicit or
+	// implicit) element index plus one. For example, the length for []int{5: 1}
+	// is 6.
+	LitMaxIndex map[*ast.CompositeLit]int64
+
+	// UnaryOps maps unary expressions (*ast.UnaryExpr) and pointer
+	// indirections (*ast.StarExpr) denoting values to the respective
+	// operator and result mode. Pointer indirections are recorded with
+	// the operator token.MUL.
+	UnaryOps map[ast.Expr]UnaryOp
+
+	// JSUnsafeIntegers records constant expressions of (typed) integer type
+	// whose value lies outside the range of integers that can be represented
+	// exactly by a float64, i.e., whose magnitude exceeds 2^53-1 (the largest
+	// "safe" integer in JavaScript). The values are valid Go constants; the
+	// information is intended for tools translating Go to languages where all
+	// numbers are floating-point values.
+	JSUnsafeIntegers map[ast.Expr]bool
+
+	// ConstantOrigin maps constant expressions to a description of how
+	// their value was obtained: "literal" for basic literals, "folded-unary",
+	// "folded-binary", and "folded-shift" for the results of constant unary,
+	// binary, and shift operations, and "imported" for qualified identifiers
+	// denoting constants of imported packages. Other constant expressions
+	// (such as identifiers or conversions) are not recorded.
+	ConstantOrigin map[ast.Expr]string
+
+	// StaticInBoundsIndex records index expressions a[i] with a constant
+	// index i that is statically known to be within the bounds of a, where a
+	// is an array, a pointer to an array, or a constant string. No run-time
+	// bounds check is needed for such index expressions.
+	StaticInBoundsIndex map[*ast.IndexExpr]bool
+
+	// AlwaysFalsePointerCompare records equality comparisons whose operands
+	// are both addresses of composite literals, as in &T{} == &T{}. Each such
+	// operand denotes a newly allocated variable, so the operands never compare
+	// equal (unless the variables have size zero, in which case the comparison
+	// is not recorded). For != the result is always true.
+	AlwaysFalsePointerCompare map[*ast.BinaryExpr]bool
+
+	// AssertInterfaceMethodCount maps type assertions x.(T) where T is an
+	// interface type to the number of methods in T's method set, including
+	// embedded methods.
+	AssertInterfaceMethodCount map[*ast.TypeAssertExpr]int
+
+	// BasicKinds maps expressions denoting values to the kind of their type's
+	// underlying type if that type is a *Basic. For untyped expressions, the
+	// recorded kind is the kind of the type recorded in Types (which remains
+	// untyped for the operands of constant expressions). Type expressions are
+	// not recorded.
+	BasicKinds map[ast.Expr]BasicKind
+
+	// OpPositions maps unary and binary expressions (including shifts and
+	// comparisons) with valid operands to the position of their operator.
+	OpPositions map[ast.Expr]token.Pos
+
+	// NeverMaterialized records untyped expressions that never received a final
+	// type, such as the operands of constant expressions (e.g., 1 and 2 in
+	// x := 1 << 2). Such expressions are not materialized at run time; their type
+	// is recorded in Types as an untyped type at the end of type checking.
+	NeverMaterialized map[ast.Expr]bool
+
+	// IsNamedType maps expressions (including type expressions) that have a
+	// type to whether that type is a named type: a predeclared or defined type,
+	// or a type parameter. It is false for type literals such as struct, slice,
+	// or map types. Expressions denoting built-in functions or calls without a
+	// value are not recorded.
+	IsNamedType map[ast.Expr]bool
+
+	// DivByZeroSites maps division and remainder expressions that are reported
+	// as divisions by zero to their (constant) divisor expression and its value.
+	// This includes constant complex divisions where the squared magnitude of the
+	// divisor underflows to zero. Assignment operations such as x /= 0 are not
+	// recorded.
+	DivByZeroSites map[*ast.BinaryExpr]struct {
+		Divisor ast.Expr
+		Val     constant.Value
+	}
+
+	// FinalDefaults maps untyped expressions that are still untyped at the end
+	// of type checking (see NeverMaterialized) to their default type, i.e., the
+	// type they would have if they were materialized (e.g., int for the
+	// operands 1 and 2 in x := 1 + 2). Types records the untyped type for
+	// such expressions. Untyped nil, which has no default type, is not
+	// recorded.
+	FinalDefaults map[ast.Expr]Type
+
+	// PartialStructLits maps struct literals without keys that specify fewer
+	// values than the struct has fields to the number of omitted (trailing)
+	// fields. It is only populated if Config.AllowPartialPositionalStructLit is
+	// set; otherwise such literals are errors.
+	PartialStructLits map[*ast.CompositeLit]int
+
+	// LitTypeAlias records composite literals whose explicit type is a (possibly
+	// qualified) type name denoting an alias, as in A{} given type A = []int.
+	LitTypeAlias map[*ast.CompositeLit]bool
+
+	// ComparisonKind maps valid comparisons to "equality" for the operators
+	// == and !=, and to "ordering" for the operators <, <=, >, and >=.
+	ComparisonKind map[*ast.BinaryExpr]string
+
+	// IdentityConversions records valid conversions T(x) where x is typed and
+	// its type is identical to T, such as int(i) for a variable i of type int.
+	// Such conversions are redundant. The map is only populated if
+	// Config.Suggestions is set.
+	IdentityConversions map[*ast.CallExpr]bool
+
+	// ImplicitConversions maps untyped expressions that are implicitly
+	// converted to a target type which is not a basic type (such as an
+	// interface, pointer, slice, map, channel, or function type) to the type
+	// chosen for them. For instance, in var i interface{} = 5, the type of 5
+	// is its default type int. The untyped nil keeps the type untyped nil.
+	// Untyped expressions converted to basic types (which simply assume the
+	// target type) are not recorded.
+	ImplicitConversions map[ast.Expr]Type
+
+	// BoolInArithmetic records unary and binary expressions with an arithmetic
+	// operator (+, -, *, /, or %) that are invalid because an operand is a
+	// boolean, as in true + 1 or -b for a variable b of type bool.
+	BoolInArithmetic map[ast.Expr]bool
+
+	// MaxLitDepth maps outermost composite literals to the maximum nesting
+	// depth of composite literals within them (including literals with elided
+	// types, as in [][]int{{1}}). A literal without nested composite literals
+	// has depth 1. Literals within function literals are counted separately.
+	MaxLitDepth map[*ast.CompositeLit]int
+
+	// Untyped maps expressions that were untyped when they were checked to
+	// their untyped type, such as untyped int for the constant 1 in x := 1.
+	// If the untyped type changes, as for the operand 2 in 1.0 + 2, which
+	// becomes an untyped float, the last untyped type is recorded. The type
+	// recorded in Types is the (final) type of the expression, which may be a
+	// typed type. See also Info.DefaultType.
+	Untyped map[ast.Expr]*Basic
+
+	// ElemPrecisionLoss maps untyped constant elements of array and slice
+	// literals with element type float32 whose value is rounded when converted
+	// to float32, as in []float32{0.1}, to their original (exact) and rounded
+	// value.
+	ElemPrecisionLoss map[ast.Expr]struct{ Orig, Rounded constant.Value }
+
+	// StaticValidSlice records slice expressions a[low : high : max] whose
+	// indices (including the default indices of omitted ones) are constants
+	// statically known to be in bounds and in order, where a is an array, a
+	// pointer to an array, or a constant string. No run-time bounds check is
+	// needed for such slice expressions.
+	StaticValidSlice map[*ast.SliceExpr]bool
+
+	// HasEffects maps expressions to whether evaluating them involves a
+	// function call or a channel receive operation, as in f()+2 or <-ch
+	// (but not 1+2 or conversions such as int(x)). Calls of built-in
+	// functions count as calls unless their result is constant, as for
+	// len of an array. Sub-expressions are recorded as well.
+	HasEffects map[ast.Expr]bool
+
+	// ExplicitZeroFields records the elements of keyed struct literals that
+	// explicitly set a field of basic type to its zero value (false, 0, or ""),
+	// as in T{Count: 0}. Such elements are redundant. The map is only populated
+	// if Config.Suggestions is set.
+	ExplicitZeroFields map[*ast.KeyValueExpr]bool
+
+	// InferredArrayLens maps array composite literals whose type has an invalid
+	// length (rather than a [...] length) to the length guessed from the number
+	// of literal elements. The guessed length is also the length of the array
+	// type recorded in Types, as for [...]T literals.
+	InferredArrayLens map[*ast.CompositeLit]int64
+
+	// ComparisonConstantSide maps valid comparisons to the operands that are
+	// constant: 0 if neither operand is constant, 1 if only the left operand is,
+	// 2 if only the right operand is, and 3 if both are. It is intended for
+	// tools that canonicalize comparisons such as 5 == x to x == 5.
+	ComparisonConstantSide map[*ast.BinaryExpr]int
+
+	// LossyConversions records constant expressions whose value changed when
+	// they were converted (explicitly or implicitly) to a typed constant, such
+	// as 0.1 in float32(0.1), which is rounded to the nearest float32 value.
+	// The keys are the converted operand expressions (for an explicit
+	// conversion, the argument); the values are always true.
+	LossyConversions map[ast.Expr]bool
+
+	// ShadowedUniverse maps identifiers used in expressions that denote a
+	// user-declared object with the name of a predeclared object (as true in
+	// true := false; if true {}) to the kind of the shadowed predeclared
+	// object: "type", "constant", "nil", or "builtin function".
+	ShadowedUniverse map[*ast.Ident]string
+
+	// ShiftToZero records shift expressions with a typed integer left operand
+	// and a constant shift count that is at least the size of the operand type
+	// in bits, if the result is always 0 (as for uint32(x) << 40). Shifts that
+	// are constant are recorded only if their (folded) value is 0.
+	ShiftToZero map[*ast.BinaryExpr]bool
+
+	// DynamicMapKeys records the keys of map composite literals that are not
+	// constant and thus cannot be checked for duplicates statically. The values
+	// are always true.
+	DynamicMapKeys map[ast.Expr]bool
+
+	// UnsignedNegations maps negations -x of constants x of unsigned integer
+	// type to the value of x and the (mathematical) result of the negation.
+	// Negated constants don't wrap around: unless the operand is 0, the result
+	// is negative and not representable, and an overflow error is reported,
+	// as for -uint8(5).
+	UnsignedNegations map[*ast.UnaryExpr]struct{ Operand, Result constant.Value }
+
+	// OmittedEmbedded maps keyed struct composite literals that don't mention
+	// all embedded fields of the struct to the omitted embedded fields, in
+	// declaration order.
+	OmittedEmbedded map[*ast.CompositeLit][]*Var
+
+	// AssertTypeExpr maps type assertions x.(T) (but not x.(type) in type
+	// switches) to the asserted type T, if T is valid. It is recorded even if
+	// the assertion itself is invalid because T cannot implement the type of x.
+	AssertTypeExpr map[*ast.TypeAssertExpr]Type
+
+	// TautologicalComparisons records comparisons of two equal constant
+	// literals, such as 3 == 3 or "a" != "a", whose result is trivially true
+	// or false. It is only populated if Config.Suggestions is set. The values
+	// are always true.
+	TautologicalComparisons map[*ast.BinaryExpr]bool
+
+	// SliceIndexAssumptions maps slice expressions s[i:j:k] of operands s of
+	// slice type (whose length is not known statically) to the values of the
+	// explicit constant indices, in source order. Such indices are in range only
+	// if the length (or capacity) of s is large enough.
+	SliceIndexAssumptions map[*ast.SliceExpr][]int64
+
+	// ArithmeticWidening maps binary expressions with untyped operands of
+	// different kinds, such as 1 + 2.0, to the widening of the operand with the
+	// "smaller" kind to the kind of the other operand (here, from UntypedInt to
+	// UntypedFloat). The resulting kind is the untyped kind of the operation
+	// (before any conversion to a typed context).
+	ArithmeticWidening map[*ast.BinaryExpr]struct{ From, To BasicKind }
 }
 
 func getInferred(info *Info) map[ast.Expr]_Inferred {
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/importer"
 	"go/internal/typeparams"
 	"go/parser"
@@ -15,6 +16,7 @@ import (
 	"internal/testenv"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"testing"
 
@@ -75,6 +77,57 @@ func mayTypecheck(t *testing.T, path, source string, info *Info) (string, error)
 	return pkg.Name(), err
 }
 
+// checkWithConfig type-checks source using conf, populating info if
+// provided, and returns the messages of all reported errors.
+func checkWithConfig(t *testing.T, conf *Config, source string, info *Info) []string {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", source, modeForSource(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errs []string
+	conf.Error = func(err error) { errs = append(errs, err.(Error).Msg) }
+	if conf.Importer == nil {
+		conf.Importer = importer.Default()
+	}
+	conf.Check(f.Name.Name, fset, []*ast.File{f}, info) // errors are collected above
+	return errs
+}
+
+// infoEntries returns the descriptions of the entries of m, which
+// must be a map with expression keys, such as Info.ShiftToZero. An entry is
+// described by its key, followed by ": " and its value unless the value is
+// the boolean true.
+func infoEntries(m interface{}) []string {
+	var list []string
+	iter := reflect.ValueOf(m).MapRange()
+	for iter.Next() {
+		s := ExprString(iter.Key().Interface().(ast.Expr))
+		if v := iter.Value(); v.Kind() != reflect.Bool || !v.Bool() {
+			s += fmt.Sprintf(": %v", v.Interface())
+		}
+		list = append(list, s)
+	}
+	return list
+}
+
+// checkInfoEntries reports an error if the descriptions of the entries
+// of the map m (see infoEntries) are not the ones in want.
+func checkInfoEntries(t *testing.T, m interface{}, want ...string) {
+	t.Helper()
+	checkSorted(t, infoEntries(m), want...)
+}
+
+// checkSorted sorts got and reports an error if it is not want.
+// It is used for Info maps whose entries need a custom description.
+func checkSorted(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	sort.Strings(got)
+	if len(got) != len(want) || len(got) > 0 && !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
 func TestValuesInfo(t *testing.T) {
 	var tests = []struct {
 		src  string
@@ -1468,6 +1521,28 @@ func TestAssignableTo(t *testing.T) {
 	}
 }
 
+func TestAssignabilityDetail(t *testing.T) {
+	empty := NewInterfaceType(nil, nil).Complete()
+	for _, test := range []struct {
+		v, t Type
+		want Assignability
+	}{
+		{Typ[Int], Typ[Int], Assignability{true, "identical"}},
+		{Typ[UntypedNil], NewSlice(Typ[Int]), Assignability{true, "nil"}},
+		{Typ[UntypedInt], Typ[Float64], Assignability{true, "untyped-convert"}},
+		{Typ[UntypedInt], empty, Assignability{true, "interface-box"}},
+		{Typ[Int], empty, Assignability{true, "interface-box"}},
+		{NewChan(SendRecv, Typ[Int]), NewChan(RecvOnly, Typ[Int]), Assignability{true, "directional-channel"}},
+		{newDefined(new(Struct)), new(Struct), Assignability{true, "unnamed-underlying"}},
+		{newDefined(Typ[Int]), Typ[Int], Assignability{}},
+		{Typ[Int], Typ[Float32], Assignability{}},
+	} {
+		if got := AssignabilityDetail(test.v, test.t); got != test.want {
+			t.Errorf("AssignabilityDetail(%v, %v) = %v, want %v", test.v, test.t, got, test.want)
+		}
+	}
+}
+
 func TestIdentical_issue15173(t *testing.T) {
 	// Identical should allow nil arguments and be symmetric.
 	for _, test := range []struct {
@@ -1704,3 +1779,2427 @@ func f(x T) T { return foo.F(x) }
 		}
 	}
 }
+
+func TestReportAllSliceIndexErrors(t *testing.T) {
+	const src = `package p; var a [10]int; var s []int; var _, _ = a[2:1:0], s[2:1:0]`
+	for _, test := range []struct {
+		all  bool
+		want []string
+	}{
+		{false, []string{
+			"swapped slice indices: 2 > 1",
+			"swapped slice indices: 2 > 1",
+		}},
+		{true, []string{
+			"swapped slice indices: 2 > 1",
+			"swapped slice indices: 2 > 0",
+			"swapped slice indices: 1 > 0",
+			"swapped slice indices: 2 > 1",
+			"swapped slice indices: 2 > 0",
+			"swapped slice indices: 1 > 0",
+		}},
+	} {
+		conf := Config{ReportAllSliceIndexErrors: test.all}
+		got := checkWithConfig(t, &conf, src, nil)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ReportAllSliceIndexErrors = %t: got %q, want %q", test.all, got, test.want)
+		}
+	}
+}
+
+func TestClampedOverflowsInfo(t *testing.T) {
+	const src = `package p; const _ = 1 << 500 * (1 << 500); const _ = 1 << 10 * 2`
+	info := Info{
+		Types:            make(map[ast.Expr]TypeAndValue),
+		ClampedOverflows: make(map[ast.Expr]bool),
+	}
+	errs := checkWithConfig(t, new(Config), src, &info)
+	if len(errs) != 1 || !strings.Contains(errs[0], "constant multiplication overflow") {
+		t.Fatalf("got errors %q, want one multiplication overflow", errs)
+	}
+	checkInfoEntries(t, info.ClampedOverflows, "1 << 500 * (1 << 500)")
+}
+
+func TestLitElemCountInfo(t *testing.T) {
+	const src = `package p; var _ = [][]int{{1, 2, 3}, {}, {4}}`
+	info := Info{LitElemCount: make(map[*ast.CompositeLit]int)}
+	mustTypecheck(t, "LitElemCount", src, &info)
+	var got []int
+	for _, n := range info.LitElemCount {
+		got = append(got, n)
+	}
+	sort.Ints(got)
+	want := []int{0, 1, 3, 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got element counts %v, want %v", got, want)
+	}
+}
+
+func TestStringByteIndexInfo(t *testing.T) {
+	const src = `package p; var s string; var a []rune; var _, _, _ = s[0], a[0], "foo"[1]`
+	info := Info{StringByteIndex: make(map[*ast.IndexExpr]bool)}
+	mustTypecheck(t, "StringByteIndex", src, &info)
+	checkInfoEntries(t, info.StringByteIndex, `"foo"[1]`, "s[0]")
+}
+
+func TestPendingShiftResultsInfo(t *testing.T) {
+	const src = `package p; var s uint; var x int64 = 1 << s; var y = 1 << 2; var z = x << s`
+	info := Info{
+		Types:               make(map[ast.Expr]TypeAndValue),
+		PendingShiftResults: make(map[ast.Expr]bool),
+	}
+	mustTypecheck(t, "PendingShiftResults", src, &info)
+	var got []string
+	for e := range info.PendingShiftResults {
+		got = append(got, ExprString(e)+": "+info.Types[e].Type.String())
+	}
+	want := []string{"1 << s: int64"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLitMaxIndexInfo(t *testing.T) {
+	for _, test := range []struct {
+		src  string
+		want int64
+	}{
+		{`package p0; var _ = []int{}`, 0},
+		{`package p1; var _ = []int{5: 1}`, 6},
+		{`package p2; var _ = []int{1, 2, 3}`, 3},
+		{`package p3; var _ = [...]int{2: 1, 0: 1, 1}`, 3},
+		{`package p4; var _ = [10]int{4: 1, 3}`, 6},
+	} {
+		info := Info{LitMaxIndex: make(map[*ast.CompositeLit]int64)}
+		name := mustTypecheck(t, "LitMaxIndex", test.src, &info)
+		if len(info.LitMaxIndex) != 1 {
+			t.Errorf("package %s: got %d entries, want 1", name, len(info.LitMaxIndex))
+			continue
+		}
+		for _, got := range info.LitMaxIndex {
+			if got != test.want {
+				t.Errorf("package %s: got %d, want %d", name, got, test.want)
+			}
+		}
+	}
+}
+
+func TestNoStringConstFold(t *testing.T) {
+	const src = `package p
+
+var (
+	s = "foo" + "bar"
+	n = 1 + 2
+	b = []byte("a" + "b")
+	r = []rune("a" + "b" + "c")
+)`
+	for _, fold := range []bool{true, false} {
+		info := Info{Types: make(map[ast.Expr]TypeAndValue)}
+		conf := Config{NoStringConstFold: !fold}
+		if errs := checkWithConfig(t, &conf, src, &info); len(errs) > 0 {
+			t.Fatalf("NoStringConstFold = %t: unexpected errors %q", !fold, errs)
+		}
+		for e, tv := range info.Types {
+			switch ExprString(e) {
+			case `"foo" + "bar"`:
+				if got := tv.Value != nil; got != fold {
+					t.Errorf("NoStringConstFold = %t: %s folded = %t, want %t", !fold, e, got, fold)
+				}
+				if tv.Type != Typ[String] {
+					t.Errorf("NoStringConstFold = %t: %s has type %s, want string", !fold, e, tv.Type)
+				}
+			case "1 + 2":
+				if tv.Value == nil {
+					t.Errorf("NoStringConstFold = %t: %s not folded", !fold, e)
+				}
+			case `"a"`, `"b"`, `"c"`:
+				// operands of folded concatenations remain untyped
+				if !fold && tv.Type != Typ[String] {
+					t.Errorf("NoStringConstFold = %t: %s has type %s, want string", !fold, e, tv.Type)
+				}
+			}
+		}
+	}
+
+	conf := Config{NoStringConstFold: true}
+	errs := checkWithConfig(t, &conf, `package p; const _ = "foo" + "bar"`, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0], "is not constant") {
+		t.Errorf("got errors %q, want one \"is not constant\" error", errs)
+	}
+}
+
+func TestUnaryOpsInfo(t *testing.T) {
+	const src = `package p
+
+func _(x int, b bool, p *int, ch chan int) {
+	_ = -x
+	_ = ^1
+	_ = !b
+	_ = &x
+	_ = *p
+	_ = <-ch
+}`
+	info := Info{UnaryOps: make(map[ast.Expr]UnaryOp)}
+	mustTypecheck(t, "UnaryOps", src, &info)
+	got := make(map[string]UnaryOp)
+	for e, op := range info.UnaryOps {
+		got[ExprString(e)] = op
+	}
+	want := map[string]UnaryOp{
+		"-x":   {token.SUB, "value"},
+		"^1":   {token.XOR, "constant"},
+		"!b":   {token.NOT, "value"},
+		"&x":   {token.AND, "value"},
+		"*p":   {token.MUL, "variable"},
+		"<-ch": {token.ARROW, "commaok"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestJSUnsafeIntegersInfo(t *testing.T) {
+	const src = `package p
+
+const big = 1 << 60
+
+var (
+	_ int64 = 1<<53 - 1
+	_ int64 = 1 << 53
+	_ int64 = -(1 << 53)
+	_ uint64 = big
+	_ float64 = big
+	_ = int64(big) + 1
+)`
+	info := Info{JSUnsafeIntegers: make(map[ast.Expr]bool)}
+	mustTypecheck(t, "JSUnsafeIntegers", src, &info)
+	checkInfoEntries(t, info.JSUnsafeIntegers, "-(1 << 53)", "1 << 53", "big", "big", "int64(big) + 1")
+}
+
+func TestConstantOriginInfo(t *testing.T) {
+	const src = `package p
+
+import "math"
+
+const c = 5
+
+var (
+	_ = 2 + 3
+	_ = -c
+	_ = 1 << 4
+	_ = math.Pi
+	_ = c
+	_ = int8(c)
+)`
+	info := Info{ConstantOrigin: make(map[ast.Expr]string)}
+	mustTypecheck(t, "ConstantOrigin", src, &info)
+	checkInfoEntries(t, info.ConstantOrigin,
+		"-c: folded-unary",
+		"1 << 4: folded-shift",
+		"1: literal",
+		"2 + 3: folded-binary",
+		"2: literal",
+		"3: literal",
+		"4: literal",
+		"5: literal",
+		"math.Pi: imported",
+	)
+
+	// The (invalid) assignment operations have no binary expressions to record.
+	info = Info{
+		ConstantOrigin:      make(map[ast.Expr]string),
+		ClampedOverflows:    make(map[ast.Expr]bool),
+		PendingShiftResults: make(map[ast.Expr]bool),
+	}
+	checkWithConfig(t, &Config{}, "package p; const c = 1; func _(s uint) { c += 2; c <<= 600; c <<= s }", &info)
+	if _, ok := info.ConstantOrigin[nil]; ok {
+		t.Error("ConstantOrigin records nil")
+	}
+	if _, ok := info.ClampedOverflows[nil]; ok {
+		t.Error("ClampedOverflows records nil")
+	}
+	if _, ok := info.PendingShiftResults[nil]; ok {
+		t.Error("PendingShiftResults records nil")
+	}
+}
+
+func TestStaticInBoundsIndexInfo(t *testing.T) {
+	const src = `package p
+
+const str = "foo"
+
+func _(a [4]int, p *[4]int, s []int, m map[int]int, i int, t string) {
+	_ = a[0]
+	_ = a[3]
+	_ = a[i]
+	_ = p[1]
+	_ = s[0]
+	_ = m[0]
+	_ = str[2]
+	_ = t[0]
+}`
+	info := Info{StaticInBoundsIndex: make(map[*ast.IndexExpr]bool)}
+	mustTypecheck(t, "StaticInBoundsIndex", src, &info)
+	checkInfoEntries(t, info.StaticInBoundsIndex, "a[0]", "a[3]", "p[1]", "str[2]")
+}
+
+func TestOverflowMessageSuffix(t *testing.T) {
+	const src = `package p; var _ int8 = 1000; var _ int = 1.5; var _ string = 1; var _ = uint8(1) << 9`
+	conf := Config{OverflowMessageSuffix: "(32-bit target)"}
+	got := checkWithConfig(t, &conf, src, nil)
+	want := []string{
+		"cannot use 1000 (untyped int constant) as int8 value in variable declaration (overflows) (32-bit target)",
+		"cannot use 1.5 (untyped float constant) as int value in variable declaration (truncated) (32-bit target)",
+		"cannot use 1 (untyped int constant) as string value in variable declaration",
+		"uint8(1) << 9 (constant 512 of type uint8) overflows uint8 (32-bit target)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestAlwaysFalsePointerCompareInfo(t *testing.T) {
+	const src = `package p
+
+type T struct{ x int }
+type E struct{}
+
+func _(p *T) {
+	_ = &T{} == &T{}
+	_ = (&T{1}) != &T{2}
+	_ = &T{} == p
+	_ = &T{} == nil
+	_ = &E{} == &E{}
+	_ = &[]int{} == &[]int{}
+}`
+	info := Info{AlwaysFalsePointerCompare: make(map[*ast.BinaryExpr]bool)}
+	mustTypecheck(t, "AlwaysFalsePointerCompare", src, &info)
+	checkInfoEntries(t, info.AlwaysFalsePointerCompare, "&(T literal) == &(T literal)", "&([]int literal) == &([]int literal)", "(&(T literal)) != &(T literal)")
+}
+
+func TestAssertInterfaceMethodCountInfo(t *testing.T) {
+	const src = `package p
+
+type I interface{ m() }
+type J interface {
+	I
+	n()
+	o()
+}
+type T struct{}
+
+func (T) m() {}
+
+func _(x interface{}) {
+	_ = x.(I)
+	_ = x.(J)
+	_ = x.(interface{})
+	_ = x.(T)
+}`
+	info := Info{AssertInterfaceMethodCount: make(map[*ast.TypeAssertExpr]int)}
+	mustTypecheck(t, "AssertInterfaceMethodCount", src, &info)
+	got := make(map[string]int)
+	for e, n := range info.AssertInterfaceMethodCount {
+		got[ExprString(e)] = n
+	}
+	want := map[string]int{"x.(I)": 1, "x.(J)": 3, "x.(interface{})": 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBasicKindsInfo(t *testing.T) {
+	const src = `package p
+
+type MyInt int32
+
+var (
+	a = 1
+	b = MyInt(2) + 3
+	c = 1.5 * 4
+	d = "s"
+	e = []int{}
+	f = int8(a)
+)`
+	info := Info{BasicKinds: make(map[ast.Expr]BasicKind)}
+	mustTypecheck(t, "BasicKinds", src, &info)
+	got := make(map[string]BasicKind)
+	for e, k := range info.BasicKinds {
+		got[ExprString(e)] = k
+	}
+	want := map[string]BasicKind{
+		"1":            Int,
+		"2":            Int32,
+		"3":            Int32,
+		"MyInt(2)":     Int32,
+		"MyInt(2) + 3": Int32,
+		"1.5":          UntypedFloat,
+		"4":            UntypedFloat,
+		"1.5 * 4":      Float64,
+		"\"s\"":        String,
+		"a":            Int,
+		"int8(a)":      Int8,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestOnCompositeLit(t *testing.T) {
+	const src = `package p
+
+type S struct{ a, b int }
+
+var (
+	_ = [3]int{1, 2}
+	_ = []S{{1, 2}, {a: 1}}
+	_ = map[string]int{"a": 1}
+)`
+	var got []string
+	conf := Config{
+		OnCompositeLit: func(lit *ast.CompositeLit, typ Type, n int) {
+			got = append(got, fmt.Sprintf("%s: %d", typ, n))
+		},
+	}
+	if errs := checkWithConfig(t, &conf, src, nil); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	want := []string{"[3]int: 2", "p.S: 2", "p.S: 1", "[]p.S: 2", "map[string]int: 1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestOpPositionsInfo(t *testing.T) {
+	const src = `package p
+
+func _(a, b int, p *int) {
+	_ = -a
+	_ = a + b
+	_ = a << b
+	_ = a  ==  b
+	_ = !(a < b)
+	_ = *p
+}`
+	info := Info{OpPositions: make(map[ast.Expr]token.Pos)}
+	mustTypecheck(t, "OpPositions", src, &info)
+	var got []string
+	for e, pos := range info.OpPositions {
+		got = append(got, fmt.Sprintf("%s @ %d", ExprString(e), pos-e.Pos())) // operator offset within e
+	}
+	checkSorted(t, got, "!(a < b) @ 0", "-a @ 0", "a + b @ 2", "a < b @ 2", "a << b @ 2", "a == b @ 3")
+}
+
+func TestNeverMaterializedInfo(t *testing.T) {
+	const src = `package p
+
+const c = 10
+
+var x = 1 << 2
+var y float64 = c + 0.5
+var z = 3
+
+func _(b bool) {
+	_ = b == (1 < 2)
+}`
+	info := Info{NeverMaterialized: make(map[ast.Expr]bool)}
+	mustTypecheck(t, "NeverMaterialized", src, &info)
+	checkInfoEntries(t, info.NeverMaterialized, "0.5", "1", "1", "10", "2", "c")
+}
+
+func TestConstEqual(t *testing.T) {
+	big := func() constant.Value { return constant.Shift(constant.MakeInt64(1), token.SHL, 100) }
+	for _, test := range []struct {
+		a, b constant.Value
+		want bool
+	}{
+		{constant.MakeBool(true), constant.MakeBool(true), true},
+		{constant.MakeString("a"), constant.MakeString("a"), true},
+		{constant.MakeString("a"), constant.MakeString("b"), false},
+		{constant.MakeInt64(1), constant.MakeInt64(1), true},
+		{constant.MakeInt64(1), constant.MakeFloat64(1), false},
+		{constant.MakeUint64(1 << 63), constant.MakeUint64(1 << 63), true},
+		{constant.MakeInt64(-1), constant.MakeUint64(1<<64 - 1), false},
+		{big(), big(), true},
+		{big(), constant.BinaryOp(big(), token.ADD, constant.MakeInt64(1)), false},
+		{constant.MakeFromLiteral("100000000000000000000", token.INT, 0), constant.ToInt(constant.MakeFromLiteral("1e20", token.FLOAT, 0)), true},
+		{constant.MakeFromLiteral("100000000000000000000", token.INT, 0), constant.MakeString("100000000000000000000"), false},
+		{constant.MakeFloat64(0.5), constant.MakeFromLiteral("0.5", token.FLOAT, 0), true},
+		{constant.MakeFromLiteral("0.1", token.FLOAT, 0), constant.MakeFromLiteral("0.10000000000000000001", token.FLOAT, 0), true},
+		{constant.MakeImag(constant.MakeInt64(1)), constant.MakeImag(constant.MakeInt64(1)), true},
+	} {
+		if got := ConstEqual(test.a, test.b); got != test.want {
+			t.Errorf("ConstEqual(%s, %s) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestIsNamedTypeInfo(t *testing.T) {
+	const src = `package p
+
+type T struct{}
+
+func f() {}
+
+var (
+	a = T{}
+	b = struct{}{}
+	c = []T{}
+	d = 1
+	_ = len(c)
+)
+
+func _() {
+	f()
+}`
+	info := Info{IsNamedType: make(map[ast.Expr]bool)}
+	mustTypecheck(t, "IsNamedType", src, &info)
+	got := make(map[string]bool)
+	for e, named := range info.IsNamedType {
+		got[ExprString(e)] = named
+	}
+	want := map[string]bool{
+		"T":                  true,
+		"(T literal)":        true,
+		"struct{}":           false,
+		"(struct{} literal)": false,
+		"[]T":                false,
+		"([]T literal)":      false,
+		"1":                  true,
+		"c":                  false,
+		"len(c)":             true,
+		"f":                  false,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestAllowIntAsBool(t *testing.T) {
+	const src = `package p
+
+var (
+	a bool = 1
+	b bool = 0
+	c bool = 2
+	d      = 1
+	e bool = d
+)
+
+func _() {
+	if 1 {
+	}
+	for 0 {
+	}
+	_ = !1
+}`
+	for _, allow := range []bool{false, true} {
+		conf := Config{AllowIntAsBool: allow}
+		want := 7
+		if allow {
+			want = 3 // c, e, and !1
+		}
+		if errs := checkWithConfig(t, &conf, src, nil); len(errs) != want {
+			t.Errorf("AllowIntAsBool = %v: got %d errors, want %d: %q", allow, len(errs), want, errs)
+		}
+	}
+
+	// 0 and 1 are recorded with type bool and a boolean value.
+	info := Info{Types: make(map[ast.Expr]TypeAndValue)}
+	checkWithConfig(t, &Config{AllowIntAsBool: true}, src, &info)
+	n := 0
+	for e, tv := range info.Types {
+		if lit, _ := e.(*ast.BasicLit); lit != nil && tv.Type == Typ[Bool] {
+			if want := lit.Value == "1"; constant.BoolVal(tv.Value) != want {
+				t.Errorf("%s: got value %s, want %v", lit.Value, tv.Value, want)
+			}
+			n++
+		}
+	}
+	if n != 4 {
+		t.Errorf("got %d boolean literals, want 4", n)
+	}
+}
+
+func TestIndexResultMode(t *testing.T) {
+	arr := NewArray(Typ[Int], 3)
+	for _, test := range []struct {
+		typ         Type
+		addressable bool
+		mode        string
+		ok          bool
+	}{
+		{arr, true, "variable", true},
+		{arr, false, "value", true},
+		{NewPointer(arr), false, "variable", true},
+		{NewSlice(Typ[Int]), false, "variable", true},
+		{Typ[String], true, "value", true},
+		{Typ[UntypedString], false, "value", true},
+		{NewMap(Typ[String], Typ[Int]), true, "mapindex", true},
+		{NewNamed(NewTypeName(token.NoPos, nil, "A", nil), arr, nil), false, "value", true},
+		{Typ[Int], true, "", false},
+		{NewPointer(Typ[Int]), true, "", false},
+		{NewChan(SendRecv, Typ[Int]), true, "", false},
+	} {
+		mode, ok := IndexResultMode(test.typ, test.addressable)
+		if mode != test.mode || ok != test.ok {
+			t.Errorf("IndexResultMode(%s, %v) = (%q, %v), want (%q, %v)", test.typ, test.addressable, mode, ok, test.mode, test.ok)
+		}
+	}
+}
+
+func TestMaxUntypedConstBits(t *testing.T) {
+	const src = `package p
+
+const c = 1 << 1000
+const _ = c >> 990
+`
+	for _, test := range []struct {
+		bits int
+		errs int
+	}{
+		{0, 1},
+		{512, 1},
+		{1024, 0},
+		{1000, 1},
+		{1001, 0},
+	} {
+		conf := Config{MaxUntypedConstBits: test.bits}
+		if errs := checkWithConfig(t, &conf, src, nil); len(errs) != test.errs {
+			t.Errorf("MaxUntypedConstBits = %d: got %d errors, want %d: %q", test.bits, len(errs), test.errs, errs)
+		}
+	}
+
+	// Invalid limits make Check fail without reporting type errors.
+	conf := Config{MaxUntypedConstBits: 32}
+	if _, err := conf.Check("p", token.NewFileSet(), nil, nil); err == nil {
+		t.Error("MaxUntypedConstBits = 32: Check succeeded, want error")
+	}
+}
+
+func TestDivByZeroSitesInfo(t *testing.T) {
+	const src = `package p
+
+const c = 0
+
+func _(x int) {
+	_ = x / c
+	_ = 1 % (0)
+	_ = 1i / 1e-600000000
+	_ = x / 1
+	x /= 0
+}`
+	info := Info{DivByZeroSites: make(map[*ast.BinaryExpr]struct {
+		Divisor ast.Expr
+		Val     constant.Value
+	})}
+	checkWithConfig(t, &Config{}, src, &info)
+	var got []string
+	for e, site := range info.DivByZeroSites {
+		got = append(got, fmt.Sprintf("%s: %s = %s", ExprString(e), ExprString(site.Divisor), site.Val))
+	}
+	checkSorted(t, got, "1 % (0): (0) = 0", "1i / 1e-600000000: 1e-600000000 = 1e-600000000", "x / c: c = 0")
+}
+
+func TestExprVisitor(t *testing.T) {
+	const src = `package p
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+type T struct{ f int }
+
+const c = 1 << 2
+
+var x = []T{{f: c}, {1}}
+
+func f(a int) (int, bool) {
+	s := "a" + "b"
+	fmt.Println(s, len(x), a > 1)
+	x = append(x, T{})
+	println((len)("abc"), unsafe.Sizeof(a), cap([3]int{}))
+	return a + 1.0, a == c
+}`
+	visit := func(info *Info) map[ast.Expr]TypeAndValue {
+		visited := make(map[ast.Expr]TypeAndValue)
+		conf := Config{
+			ExprVisitor: func(e ast.Expr, tv TypeAndValue) {
+				if _, ok := visited[e]; ok {
+					t.Errorf("%s visited more than once", ExprString(e))
+				}
+				visited[e] = tv
+			},
+		}
+		if errs := checkWithConfig(t, &conf, src, info); len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		return visited
+	}
+
+	info := Info{Types: make(map[ast.Expr]TypeAndValue)}
+	visited := visit(&info)
+	if !reflect.DeepEqual(visited, info.Types) {
+		t.Errorf("visited %d expressions, Info.Types has %d entries", len(visited), len(info.Types))
+	}
+
+	// The same expressions, including built-ins, are visited without Info.
+	describe := func(m map[ast.Expr]TypeAndValue) []string {
+		var list []string
+		for e, tv := range m {
+			list = append(list, fmt.Sprintf("%d: %s: %s", e.Pos(), ExprString(e), tv.Type))
+		}
+		sort.Strings(list)
+		return list
+	}
+	checkSorted(t, describe(visit(nil)), describe(info.Types)...)
+}
+
+func TestFinalDefaultsInfo(t *testing.T) {
+	const src = `package p
+
+var x = 1 << 2.0
+var y = 'a' + 1 == 2.5i
+var z = 3
+
+func _(p *int) {
+	_ = p == nil
+}`
+	info := Info{FinalDefaults: make(map[ast.Expr]Type)}
+	mustTypecheck(t, "FinalDefaults", src, &info)
+	checkInfoEntries(t, info.FinalDefaults, "'a' + 1: complex128", "'a': rune", "1: int", "1: rune", "2.5i: complex128")
+}
+
+func TestForbidPositionalUnexported(t *testing.T) {
+	const src = `package p
+
+type T struct {
+	X int
+	y int
+}
+
+type E struct{ X, Y int }
+
+var (
+	_ = T{1, 2}
+	_ = T{X: 1, y: 2}
+	_ = E{1, 2}
+	_ = struct{ a int }{1}
+)`
+	for _, forbid := range []bool{false, true} {
+		conf := Config{ForbidPositionalUnexported: forbid}
+		errs := checkWithConfig(t, &conf, src, nil)
+		var want []string
+		if forbid {
+			want = []string{
+				"implicit assignment to unexported field y in T literal (use a keyed literal)",
+				"implicit assignment to unexported field a in struct{a int} literal (use a keyed literal)",
+			}
+		}
+		if !reflect.DeepEqual(errs, want) {
+			t.Errorf("ForbidPositionalUnexported = %v: got %q, want %q", forbid, errs, want)
+		}
+	}
+}
+
+func TestAssignableToReason(t *testing.T) {
+	const src = `package p
+
+type I interface{ m(); n() }
+type T struct{}
+
+func (T) m() {}
+
+type P struct{}
+
+func (*P) m() {}
+func (*P) n() {}
+
+type A int
+type B int
+
+type S1 []int
+type S2 []string
+
+var (
+	c  chan int
+	rc <-chan int
+	sc chan<- int
+	pi *int
+	ps *string
+)
+`
+	pkg, err := pkgFor("AssignableToReason", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(name string) Type { return pkg.Scope().Lookup(name).Type() }
+
+	for _, test := range []struct {
+		V, T Type
+		want string // regexp; empty if assignable
+	}{
+		{lookup("T"), lookup("T"), ""},
+		{NewPointer(lookup("P")), lookup("I"), ""},
+		{lookup("T"), lookup("I"), "^p.T is not assignable to p.I: missing method n$"},
+		{lookup("P"), lookup("I"), "missing method m \\(m has pointer receiver\\)"},
+		{lookup("A"), lookup("B"), "differing named types"},
+		{lookup("S1"), lookup("S2"), "differing element types"},
+		{lookup("pi"), lookup("ps"), "differing element types"},
+		{lookup("rc"), lookup("sc"), "differing channel directions"},
+		{lookup("c"), lookup("rc"), ""},
+		{NewArray(Typ[Int], 2), NewArray(Typ[Int], 3), "differing array lengths"},
+		{Typ[UntypedBool], Typ[Int], "untyped value"},
+		{Typ[Int], Typ[String], "^int is not assignable to string: incompatible types$"},
+	} {
+		ok, reason := AssignableToReason(test.V, test.T)
+		if ok != (test.want == "") || ok != AssignableTo(test.V, test.T) {
+			t.Errorf("AssignableToReason(%s, %s) = %v (%q)", test.V, test.T, ok, reason)
+			continue
+		}
+		if test.want != "" && !regexp.MustCompile(test.want).MatchString(reason) {
+			t.Errorf("AssignableToReason(%s, %s): got reason %q, want match for %q", test.V, test.T, reason, test.want)
+		}
+	}
+}
+
+func TestTraceHint(t *testing.T) {
+	const src = `package p
+
+type P struct{ x, y int }
+
+var _ = map[P][]*P{{1, 2}: {{3, 4}, nil}}
+`
+	var got []string
+	conf := Config{
+		TraceHint: func(e ast.Expr, hint Type) {
+			s := ExprString(e)
+			if _, ok := e.(*ast.CompositeLit); ok {
+				s = "{…}"
+			}
+			got = append(got, fmt.Sprintf("%s: %s", s, hint))
+		},
+	}
+	if errs := checkWithConfig(t, &conf, src, nil); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	want := []string{
+		"{…}: p.P",
+		"{…}: []*p.P",
+		"{…}: *p.P",
+		"nil: *p.P",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestIdenticalMatchesChecker(t *testing.T) {
+	// Arithmetic operands must have identical types; comparison
+	// operands must be assignable to each other.
+	const src = `package p
+
+type (
+	A int
+	B = int
+	I interface{ m() }
+	J interface{ m() }
+	K interface{ I }
+	S struct{ f int "tag" }
+	T struct{ f int }
+)
+
+func _(a A, b B, i int, s []int, m map[int]I, j J, k K, x struct{ f int }, y S, z T) {
+	_ = a + 1
+	_ = a + i
+	_ = b + i
+	_ = b + a
+	_ = s == nil
+	_ = m[0] == j
+	_ = j == k
+	_ = x == y
+	_ = x == z
+	_ = y == z
+	_ = i == a
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mismatched := make(map[token.Pos]bool)
+	info := Info{Types: make(map[ast.Expr]TypeAndValue)}
+	conf := Config{Error: func(err error) {
+		if err := err.(Error); strings.Contains(err.Msg, "mismatched types") {
+			mismatched[err.Pos] = true
+		}
+	}}
+	conf.Check("p", fset, []*ast.File{f}, &info)
+	ast.Inspect(f, func(n ast.Node) bool {
+		b, _ := n.(*ast.BinaryExpr)
+		if b == nil {
+			return true
+		}
+		tx, ty := info.Types[b.X].Type, info.Types[b.Y].Type
+		want := !mismatched[b.Pos()]
+		var got bool
+		if b.Op == token.EQL {
+			got = AssignableTo(tx, ty) || AssignableTo(ty, tx)
+		} else {
+			got = Identical(tx, ty)
+		}
+		if got != want {
+			t.Errorf("%s (types %s and %s): got %v, want %v", ExprString(b), tx, ty, got, want)
+		}
+		return true
+	})
+	if len(mismatched) != 5 {
+		t.Errorf("got %d mismatched types errors, want 5", len(mismatched))
+	}
+}
+
+func TestAllowPartialPositionalStructLit(t *testing.T) {
+	const src = `package p
+
+type T struct{ a, b, c int }
+
+var (
+	_ = T{1, 2}
+	_ = T{1, 2, 3}
+	_ = T{a: 1}
+	_ = T{1, 2, 3, 4}
+)`
+	for _, allow := range []bool{false, true} {
+		conf := Config{AllowPartialPositionalStructLit: allow}
+		info := Info{PartialStructLits: make(map[*ast.CompositeLit]int)}
+		errs := checkWithConfig(t, &conf, src, &info)
+		want := []string{"too few values in struct literal", "too many values in struct literal"}
+		if allow {
+			want = want[1:]
+		}
+		if !reflect.DeepEqual(errs, want) {
+			t.Errorf("AllowPartialPositionalStructLit = %v: got errors %q, want %q", allow, errs, want)
+		}
+		var got []string
+		for lit, n := range info.PartialStructLits {
+			got = append(got, fmt.Sprintf("%s: %d", ExprString(lit.Elts[0]), n))
+		}
+		var wantLits []string
+		if allow {
+			wantLits = []string{"1: 1"}
+		}
+		if !reflect.DeepEqual(got, wantLits) {
+			t.Errorf("AllowPartialPositionalStructLit = %v: got %q, want %q", allow, got, wantLits)
+		}
+	}
+}
+
+func TestMaxShiftCount(t *testing.T) {
+	for _, test := range []struct {
+		conf Config
+		src  string
+		want string // error; or empty
+	}{
+		{Config{MaxUntypedConstBits: 2048}, "1 << 1074", ""},
+		{Config{MaxUntypedConstBits: 2048}, "1 << 1075", "invalid shift count 1075 (constant of type uint): must be <= 1074"},
+		{Config{}, "1 << 1074", "constant shift overflow"},
+		{Config{MaxShiftCount: 10}, "1 << 10", ""},
+		{Config{MaxShiftCount: 10}, "1 << 11", "invalid shift count 11 (constant of type uint): must be <= 10"},
+		{Config{MaxShiftCount: 2000, MaxUntypedConstBits: 4096}, "1 << 2000", ""},
+		{Config{}, "1 << n", "negative shift count n (untyped int constant -3)"},
+		{Config{}, "1 << -1", "negative shift count -1 (untyped int constant)"},
+	} {
+		src := "package p; const n = -3; const _ = " + test.src
+		errs := checkWithConfig(t, &test.conf, src, nil)
+		if test.want == "" && len(errs) > 0 || test.want != "" && (len(errs) != 1 || !strings.Contains(errs[0], test.want)) {
+			t.Errorf("%s: got errors %q, want %q", test.src, errs, test.want)
+		}
+	}
+}
+
+func TestLitTypeAliasInfo(t *testing.T) {
+	const src = `package p
+
+import "go/token"
+
+type (
+	M map[string]int
+	A = map[string]int
+	B = M
+	P = token.Position
+)
+
+var (
+	_ = M{}
+	_ = A{"a": 1}
+	_ = B{}
+	_ = P{}
+	_ = token.Position{}
+	_ = map[string]int{}
+	_ = []A{{}}
+)`
+	info := Info{LitTypeAlias: make(map[*ast.CompositeLit]bool)}
+	checkWithConfig(t, &Config{}, src, &info)
+	var got []string
+	for e := range info.LitTypeAlias {
+		got = append(got, ExprString(e.Type))
+	}
+	checkSorted(t, got, "A", "B", "P")
+}
+
+func TestRepresentableAll(t *testing.T) {
+	vals := []constant.Value{
+		constant.MakeInt64(127),
+		constant.MakeInt64(128),
+		constant.MakeFloat64(3.5),
+		constant.MakeInt64(1 << 40),
+		constant.MakeString("foo"),
+		constant.MakeBool(true),
+		constant.MakeUnknown(),
+	}
+	types := []*Basic{
+		Typ[Int8],
+		Typ[Int8],
+		Typ[Int],
+		Typ[Int],
+		Typ[Int],
+		Typ[Bool],
+		Typ[Int],
+	}
+	for _, test := range []struct {
+		sizes Sizes
+		want  []string
+	}{
+		{nil, []string{"", "128 overflows int8", "3.5 truncated to int (would be 3)", "", "cannot convert \"foo\" to int", "", ""}},
+		{SizesFor("gc", "386"), []string{"", "128 overflows int8", "3.5 truncated to int (would be 3)", "1099511627776 overflows int", "cannot convert \"foo\" to int", "", ""}},
+	} {
+		errs := RepresentableAll(vals, types, test.sizes)
+		got := make([]string, len(errs))
+		for i, err := range errs {
+			if err != nil {
+				got[i] = err.Error()
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("sizes = %v: got %q, want %q", test.sizes, got, test.want)
+		}
+	}
+}
+
+func TestConstFoldBudgetBits(t *testing.T) {
+	// Each shift by 100000 adds 100000 bits to the constant; without
+	// a budget, the folded values add up to about 500 million bits.
+	src := "package p\n\nconst c = 1" + strings.Repeat(" << 100000", 100) + " >> 9999999\n\nconst d = 1 << 10\n"
+	conf := Config{
+		MaxUntypedConstBits: 1 << 24,
+		MaxShiftCount:       1 << 20,
+		ConstFoldBudgetBits: 1 << 20,
+	}
+	errs := checkWithConfig(t, &conf, src, nil)
+	want := []string{"constant expression exceeds folding budget of 1048576 bits"}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("got %q, want %q", errs, want)
+	}
+
+	// The budget is per declaration.
+	src = "package p\n\nconst c = 1" + strings.Repeat(" << 100000", 3) + "\nconst d = 1" + strings.Repeat(" << 100000", 3) + "\n"
+	if errs := checkWithConfig(t, &conf, src, nil); len(errs) > 0 {
+		t.Errorf("got %q, want no errors", errs)
+	}
+}
+
+func TestOpSuggestions(t *testing.T) {
+	const src = `
+package p
+
+var (
+	f float64
+	b bool
+	i int
+	s string
+)
+
+var (
+	_ = f % 2
+	_ = b & b
+	_ = !i
+	_ = i + i
+	_ = ^b
+	_ = s - s
+	_ = -s
+	_ = i + b
+	_ = b * 2
+)
+`
+	for _, suggest := range []bool{false, true} {
+		conf := Config{Suggestions: suggest}
+		errs := checkWithConfig(t, &conf, src, nil)
+		want := []string{
+			"invalid operation: operator % not defined for f (variable of type float64)",
+			"invalid operation: operator & not defined for b (variable of type bool)",
+			"invalid operation: operator ! not defined for i (variable of type int)",
+			"invalid operation: operator ^ not defined for b (variable of type bool)",
+			"invalid operation: operator - not defined for s (variable of type string)",
+			"invalid operation: operator - not defined for s (variable of type string)",
+			"invalid operation: operator + not defined for b (variable of type bool) (booleans cannot be used in arithmetic)",
+			"invalid operation: operator * not defined for b (variable of type bool) (booleans cannot be used in arithmetic)",
+		}
+		if suggest {
+			want = []string{
+				want[0] + " (use math.Mod, or convert to an integer type)",
+				want[1] + " (use && instead)",
+				want[2] + " (use ^ for bitwise complement, or compare with 0)",
+				want[3] + " (use ! for negation)",
+				want[4] + " (use strings.TrimSuffix or strings.ReplaceAll)",
+				want[5], // no suggestion for unary -
+				want[6], // no suggestion for mixed int and bool operands
+				want[7],
+			}
+		}
+		if !reflect.DeepEqual(errs, want) {
+			t.Errorf("Suggestions = %v: got %q, want %q", suggest, errs, want)
+		}
+	}
+}
+
+func TestOnDuplicateLitKey(t *testing.T) {
+	const src = `
+package p
+
+var _ = map[string]int{
+	"a": 1,
+	"b": 2,
+	"a": 3,
+}
+
+var _ = []int{1, 2, 0: 3}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	conf := Config{
+		Error: func(error) {},
+		OnDuplicateLitKey: func(key ast.Expr, firstPos, dupPos token.Pos) {
+			got = append(got, fmt.Sprintf("%s %s %s", ExprString(key), fset.Position(firstPos), fset.Position(dupPos)))
+		},
+	}
+	conf.Check(f.Name.Name, fset, []*ast.File{f}, nil)
+
+	want := []string{
+		`"a" p.go:5:2 p.go:7:2`,
+		`0 p.go:10:15 p.go:10:21`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestComparisonKind(t *testing.T) {
+	const src = `
+package p
+
+var x, y int
+var s []int
+
+var (
+	_ = x == y
+	_ = x != 0
+	_ = x < y
+	_ = 1 >= 2
+	_ = s == nil
+	_ = s == s // invalid
+)
+`
+	info := Info{ComparisonKind: make(map[*ast.BinaryExpr]string)}
+	pkgFor("p", src, &info) // ignore error for invalid comparison
+
+	checkInfoEntries(t, info.ComparisonKind,
+		"1 >= 2: ordering",
+		"s == nil: equality",
+		"x != 0: equality",
+		"x < y: ordering",
+		"x == y: equality",
+	)
+}
+
+func TestIdentityConversions(t *testing.T) {
+	const src = `
+package p
+
+type T int
+
+const c int = 1
+
+var (
+	i int
+	t T
+	f float64
+)
+
+var (
+	_ = int(i)
+	_ = int(c)
+	_ = T(t)
+	_ = T(i)
+	_ = int(1)
+	_ = float64(i)
+	_ = (float64)(f)
+)
+`
+	for _, suggest := range []bool{false, true} {
+		conf := Config{Suggestions: suggest}
+		info := Info{IdentityConversions: make(map[*ast.CallExpr]bool)}
+		if errs := checkWithConfig(t, &conf, src, &info); len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		var want []string
+		if suggest {
+			want = []string{"(float64)(f)", "T(t)", "int(c)", "int(i)"}
+		}
+		checkInfoEntries(t, info.IdentityConversions, want...)
+	}
+}
+
+func TestIndexMayPanic(t *testing.T) {
+	const src = `package p
+
+func _(a [4]int, s []int, i int) {
+	_ = a[0]
+	_ = a[i]
+	_ = s[0]
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := Info{StaticInBoundsIndex: make(map[*ast.IndexExpr]bool)}
+	var conf Config
+	if _, err := conf.Check(f.Name.Name, fset, []*ast.File{f}, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		if e, _ := n.(*ast.IndexExpr); e != nil {
+			got = append(got, fmt.Sprintf("%s: %v %v", ExprString(e), IndexMayPanic(&info, e), IndexMayPanic(nil, e)))
+		}
+		return true
+	})
+	want := []string{"a[0]: false true", "a[i]: true true", "s[0]: true true"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestIndexViaPointer(t *testing.T) {
+	const src = `package p
+
+type S struct{ f [2]int }
+
+func _(a [4]int, p *[4]int, s []int, q *[1]S) {
+	_ = (&a)[0]
+	_ = ((&a)[1])
+	_ = a[0]
+	_ = p[2]
+	_ = s[0]
+	_ = q[0].f
+	_ = q[0].f[1]
+	_ = (&a)[0] + 1
+	_ = (*p)[3]
+	_ = &p[1]
+	_ = q[0].f[:]
+}`
+	var visited []string
+	conf := Config{ExprVisitor: func(e ast.Expr, tv TypeAndValue) {
+		if tv.IndexViaPointer() {
+			visited = append(visited, ExprString(e))
+		}
+	}}
+	info := Info{Types: make(map[ast.Expr]TypeAndValue)}
+	if errs := checkWithConfig(t, &conf, src, &info); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	var got []string
+	for e, tv := range info.Types {
+		if tv.IndexViaPointer() {
+			got = append(got, ExprString(e))
+		}
+	}
+	want := []string{"(&a)[0]", "(&a)[0]", "(&a)[1]", "((&a)[1])", "p[1]", "p[2]", "q[0]", "q[0]", "q[0]"}
+	checkSorted(t, got, want...)
+	checkSorted(t, visited, want...) // the ExprVisitor sees the same expressions
+}
+
+func TestImplicitConversions(t *testing.T) {
+	const src = `package p
+
+var (
+	i interface{} = 5
+	f float64     = 1
+	p *int        = nil
+	_             = 'a'
+)
+
+func _() {
+	_ = i == "foo"
+	_ = i == f
+	_ = p != nil
+	_ = f == 2
+}`
+	info := Info{ImplicitConversions: make(map[ast.Expr]Type)}
+	mustTypecheck(t, "ImplicitConversions", src, &info)
+	checkInfoEntries(t, info.ImplicitConversions,
+		`"foo": string`,
+		"5: int",
+		"nil: untyped nil",
+		"nil: untyped nil",
+	)
+}
+
+func TestTraceTypeUpdate(t *testing.T) {
+	const src = `package p
+
+func _(s uint) {
+	var x int64 = 1 + 2*3<<s
+	_ = x
+}`
+	var got []string
+	conf := Config{
+		TraceTypeUpdate: func(e ast.Expr, old, new Type, final bool) {
+			got = append(got, fmt.Sprintf("%s: %s -> %s (final = %v)", ExprString(e), old, new, final))
+		},
+	}
+	if errs := checkWithConfig(t, &conf, src, nil); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	want := []string{
+		"1: untyped int -> int64 (final = false)",
+		"2 * 3: untyped int -> int64 (final = false)",
+		"2 * 3 << s: untyped int -> int64 (final = false)",
+		"1 + 2 * 3 << s: untyped int -> int64 (final = false)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestBoolInArithmetic(t *testing.T) {
+	const src = `package p
+
+var b bool
+
+var (
+	_ = true + 1
+	_ = 2 * b
+	_ = -b
+	_ = b && true
+)
+
+func _() {
+	x := 0
+	x += b
+}`
+	info := Info{BoolInArithmetic: make(map[ast.Expr]bool)}
+	checkWithConfig(t, &Config{}, src, &info) // errors are tested in testdata/check/expr1.src
+	checkInfoEntries(t, info.BoolInArithmetic, "-b", "2 * b", "true + 1")
+
+	// suggestions for binary operators don't apply to unary ones,
+	// nor to operations with non-boolean operands
+	errs := checkWithConfig(t, &Config{Suggestions: true}, "package p; var b bool; var _, _, _ = b + b, +b, !b + 1", nil)
+	want := []string{
+		"invalid operation: operator + not defined for b (variable of type bool) (booleans cannot be used in arithmetic; use || instead)",
+		"invalid operation: operator + not defined for b (variable of type bool) (booleans cannot be used in arithmetic)",
+		"invalid operation: operator + not defined for !b (value of type bool) (booleans cannot be used in arithmetic)",
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("got %q, want %q", errs, want)
+	}
+}
+
+func TestMaxLitDepth(t *testing.T) {
+	const src = `package p
+
+type T struct {
+	a []int
+	b map[string][]T
+}
+
+var (
+	_ = []int{1, 2}
+	_ = [][]int{{1}, []int{2}, {}}
+	_ = T{b: map[string][]T{"x": {{a: []int{1}}}}}
+	_ = []func(){func() { _ = [][]int{{1}} }}
+)`
+	info := Info{MaxLitDepth: make(map[*ast.CompositeLit]int)}
+	mustTypecheck(t, "MaxLitDepth", src, &info)
+
+	var got []string
+	for lit, depth := range info.MaxLitDepth {
+		got = append(got, fmt.Sprintf("%s: %d", ExprString(lit.Type), depth))
+	}
+	checkSorted(t, got,
+		"T: 5",
+		"[][]int: 2",
+		"[][]int: 2",
+		"[]func(): 1",
+		"[]int: 1",
+	)
+}
+
+func TestBinaryOpValid(t *testing.T) {
+	myInt := NewNamed(NewTypeName(token.NoPos, nil, "myInt", nil), Typ[Int], nil)
+	intSlice := NewSlice(Typ[Int])
+	for _, test := range []struct {
+		op     token.Token
+		x, y   Type
+		reason string // empty if valid
+	}{
+		{token.ADD, Typ[Int], Typ[Int], ""},
+		{token.ADD, Typ[String], Typ[UntypedString], ""},
+		{token.ADD, Typ[UntypedInt], Typ[Float64], ""},
+		{token.ADD, Typ[Int], Typ[Float64], "invalid operation: mismatched types int and float64 (consider converting x to float64)"},
+		{token.ADD, Typ[Int], myInt, "invalid operation: mismatched types int and myInt"},
+		{token.ADD, Typ[Bool], Typ[Bool], "invalid operation: operator + not defined for x (value of type bool) (booleans cannot be used in arithmetic)"},
+		{token.REM, Typ[Float64], Typ[UntypedInt], "invalid operation: operator % not defined for x (value of type float64)"},
+		{token.LAND, Typ[UntypedBool], Typ[Bool], ""},
+		{token.SHL, Typ[Uint8], Typ[Int], ""},
+		{token.SHL, Typ[UntypedInt], Typ[UntypedInt], ""},
+		{token.SHL, Typ[UntypedFloat], Typ[UntypedInt], "invalid operation: shifted operand x (untyped float value) must be integer"},
+		{token.SHL, Typ[Float64], Typ[Uint], "invalid operation: shifted operand x (value of type float64) must be integer"},
+		{token.SHR, Typ[Int], Typ[String], "invalid operation: shift count y (value of type string) must be integer"},
+		{token.EQL, Typ[Int], myInt, "cannot compare x == y (mismatched types int and myInt)"},
+		{token.EQL, Typ[UntypedInt], myInt, ""},
+		{token.EQL, intSlice, Typ[UntypedNil], ""},
+		{token.EQL, intSlice, intSlice, "cannot compare x == y (operator == not defined for []int)"},
+		{token.NEQ, Typ[UntypedNil], Typ[UntypedNil], "cannot compare x != y (operator != not defined for untyped nil)"},
+		{token.LSS, Typ[String], Typ[String], ""},
+		{token.LSS, Typ[Complex128], Typ[Complex128], "cannot compare x < y (operator < not defined for complex128)"},
+		{token.ARROW, Typ[Int], Typ[Int], "invalid AST: unknown operator <-"},
+		{token.ADD, Typ[Invalid], Typ[Int], "invalid operand type"},
+		{token.EQL, Typ[Int], Typ[Invalid], "invalid operand type"},
+	} {
+		ok, reason := BinaryOpValid(test.op, test.x, test.y)
+		if ok != (test.reason == "") || reason != test.reason {
+			t.Errorf("BinaryOpValid(%s, %s, %s) = %v, %q; want %q", test.op, test.x, test.y, ok, reason, test.reason)
+		}
+	}
+}
+
+func TestInfoDefaultType(t *testing.T) {
+	const src = `package p
+
+func _(s uint) {
+	x := 1
+	y := 1.0
+	v := 1.0 + 2
+	var z float32 = 'a'
+	var p *int = nil
+	_, _, _, _, _ = x, y, v, z, p
+	_ = x + 2<<s
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := Info{
+		Types:   make(map[ast.Expr]TypeAndValue),
+		Untyped: make(map[ast.Expr]*Basic),
+	}
+	var conf Config
+	if _, err := conf.Check(f.Name.Name, fset, []*ast.File{f}, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		if e, _ := n.(ast.Expr); e != nil {
+			if typ, ok := info.DefaultType(e); ok {
+				got = append(got, fmt.Sprintf("%s: %s -> %s (recorded %s)", ExprString(e), info.Untyped[e], typ, info.Types[e].Type))
+			}
+		}
+		return true
+	})
+	want := []string{
+		"1: untyped int -> int (recorded int)",
+		"1.0: untyped float -> float64 (recorded float64)",
+		"1.0 + 2: untyped float -> float64 (recorded float64)",
+		"1.0: untyped float -> float64 (recorded untyped float)",
+		"2: untyped float -> float64 (recorded untyped float)",
+		"'a': untyped rune -> rune (recorded float32)",
+		"nil: untyped nil -> untyped nil (recorded untyped nil)",
+		"2 << s: untyped int -> int (recorded int)",
+		"2: untyped int -> int (recorded int)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if typ, ok := info.DefaultType(f.Name); ok {
+		t.Errorf("DefaultType(%s) = %s, want (nil, false)", f.Name, typ)
+	}
+}
+
+func TestElemPrecisionLoss(t *testing.T) {
+	const src = `package p
+
+type F float32
+
+var (
+	_ = []float32{0.1, 0.5, 2: 16777217, 1 << 24}
+	_ = [...]F{0.25, 1.0 / 3}
+	_ = []float64{0.1}
+)`
+	info := Info{ElemPrecisionLoss: make(map[ast.Expr]struct{ Orig, Rounded constant.Value })}
+	mustTypecheck(t, "ElemPrecisionLoss", src, &info)
+
+	var got []string
+	for e, loss := range info.ElemPrecisionLoss {
+		got = append(got, fmt.Sprintf("%s: %s -> %s", ExprString(e), loss.Orig.ExactString(), loss.Rounded.ExactString()))
+	}
+	checkSorted(t, got,
+		"0.1: 1/10 -> 13421773/134217728",
+		"1.0 / 3: 1/3 -> 11184811/33554432",
+		"16777217: 16777217 -> 16777216",
+	)
+}
+
+func TestNamedIndexType(t *testing.T) {
+	const src = `package p
+
+type Idx int
+
+func _(a [4]string, s []float64, i Idx) {
+	_ = a[Idx(2)]
+	_ = s[i]
+	_ = s[i:Idx(3)]
+}`
+	info := Info{Types: make(map[ast.Expr]TypeAndValue)}
+	mustTypecheck(t, "NamedIndexType", src, &info)
+
+	var got []string
+	for e, tv := range info.Types {
+		switch e := e.(type) {
+		case *ast.IndexExpr:
+			got = append(got, fmt.Sprintf("%s: %s, index %s: %s", ExprString(e), tv.Type, ExprString(e.Index), info.Types[e.Index].Type))
+		case *ast.SliceExpr:
+			got = append(got, fmt.Sprintf("%s: %s, indices %s: %s, %s: %s", ExprString(e), tv.Type,
+				ExprString(e.Low), info.Types[e.Low].Type, ExprString(e.High), info.Types[e.High].Type))
+		}
+	}
+	checkSorted(t, got,
+		"a[Idx(2)]: string, index Idx(2): p.Idx",
+		"s[i:Idx(3)]: []float64, indices i: p.Idx, Idx(3): p.Idx",
+		"s[i]: float64, index i: p.Idx",
+	)
+}
+
+func TestComparableReason(t *testing.T) {
+	const src = `package p
+
+type (
+	T0 struct{ a, b int }
+	T1 struct{ a int; S []int }
+	T2 [4]func()
+	T3 struct{ t T1 }
+	T4 struct{ i interface{}; p *T4 }
+	T5 map[int]bool
+)`
+	pkg, err := pkgFor("p", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name, reason string // reason is empty for comparable types
+	}{
+		{"T0", ""},
+		{"T1", "slice field S makes struct non-comparable"},
+		{"T2", "array element type func() is not comparable"},
+		{"T3", "struct field t makes struct non-comparable: slice field S makes struct non-comparable"},
+		{"T4", ""},
+		{"T5", "map type"},
+	} {
+		typ := pkg.Scope().Lookup(test.name).Type()
+		ok, reason := ComparableReason(typ)
+		if ok != (test.reason == "") || reason != test.reason {
+			t.Errorf("ComparableReason(%s) = %v, %q; want %q", typ, ok, reason, test.reason)
+		}
+		if ok != Comparable(typ) {
+			t.Errorf("ComparableReason(%s) = %v, but Comparable(%s) = %v", typ, ok, typ, !ok)
+		}
+	}
+}
+
+func TestStaticValidSlice(t *testing.T) {
+	const src = `package p
+
+const str = "foo"
+
+func _(a [4]int, p *[4]int, s []int, t string, i int) {
+	_ = a[:]
+	_ = a[1:3]
+	_ = a[1:2:4]
+	_ = a[i:]
+	_ = a[:i]
+	_ = p[2:]
+	_ = s[0:0]
+	_ = str[1:]
+	_ = t[0:0]
+}`
+	info := Info{StaticValidSlice: make(map[*ast.SliceExpr]bool)}
+	mustTypecheck(t, "StaticValidSlice", src, &info)
+
+	checkInfoEntries(t, info.StaticValidSlice, "a[1:2:4]", "a[1:3]", "a[:]", "p[2:]", "str[1:]")
+
+	// invalid slice expressions are not recorded
+	const invalid = `package p; var a [4]int; var _, _ = a[5:], a[3:1]`
+	info.StaticValidSlice = make(map[*ast.SliceExpr]bool)
+	pkgFor("p", invalid, &info)
+	if len(info.StaticValidSlice) > 0 {
+		t.Errorf("got %d recorded slice expressions, want none", len(info.StaticValidSlice))
+	}
+}
+
+func TestDefaultChangesValue(t *testing.T) {
+	for _, test := range []struct {
+		val  constant.Value
+		kind BasicKind
+		want bool
+	}{
+		{constant.MakeBool(true), UntypedBool, false},
+		{constant.MakeString("foo"), UntypedString, false},
+		{constant.MakeInt64(42), UntypedInt, false},
+		{constant.MakeInt64('a'), UntypedRune, false},
+		{constant.MakeFloat64(0.5), UntypedFloat, false},
+		{constant.MakeFromLiteral("0.1", token.FLOAT, 0), UntypedFloat, true},
+		{constant.MakeFromLiteral("1e1000", token.FLOAT, 0), UntypedFloat, true},
+		{constant.MakeFromLiteral("1i", token.IMAG, 0), UntypedComplex, false},
+		{constant.MakeFromLiteral("0.1i", token.IMAG, 0), UntypedComplex, true},
+		{constant.Shift(constant.MakeInt64(1), token.SHL, 63), UntypedInt, true},
+		{constant.MakeFromLiteral("0.1", token.FLOAT, 0), Float32, true},
+		{constant.MakeUnknown(), UntypedFloat, false},
+		{constant.MakeInt64(0), UntypedNil, false},
+	} {
+		if got := DefaultChangesValue(test.val, test.kind); got != test.want {
+			t.Errorf("DefaultChangesValue(%s, %s) = %v, want %v", test.val, Typ[test.kind], got, test.want)
+		}
+	}
+}
+
+func TestStrictShiftOperands(t *testing.T) {
+	const src = `package p
+
+const (
+	_ = 2.0 << 1
+	_ = 2 << 1
+	_ = 'a' << 1
+)
+
+var s uint
+var _ int = 2.0 << s
+var _ float64 = 2.0 << s`
+	for _, strict := range []bool{false, true} {
+		conf := Config{StrictShiftOperands: strict}
+		errs := checkWithConfig(t, &conf, src, nil)
+		want := []string{
+			"invalid operation: shifted operand 2.0 (type float64) must be integer",
+		}
+		if strict {
+			// only one error per operand, even if the shift is not constant
+			want = []string{
+				"invalid operation: shifted operand 2.0 (untyped float constant 2) must be an integer constant (float literal not allowed)",
+				"invalid operation: shifted operand 2.0 (untyped float constant 2) must be an integer constant (float literal not allowed)",
+				"invalid operation: shifted operand 2.0 (untyped float constant 2) must be an integer constant (float literal not allowed)",
+			}
+		}
+		if !reflect.DeepEqual(errs, want) {
+			t.Errorf("StrictShiftOperands = %v: got %q, want %q", strict, errs, want)
+		}
+	}
+}
+
+func TestHasEffects(t *testing.T) {
+	const src = `package p
+
+func f() int
+
+func _(ch chan int, a [4]int, s []int) {
+	_ = 1 + 2
+	_ = f() + 2
+	_ = <-ch
+	_ = len(a)
+	_ = len(s)
+	_ = int64(len(s) + f())
+	ch <- 0
+}`
+	info := Info{HasEffects: make(map[ast.Expr]bool)}
+	mustTypecheck(t, "HasEffects", src, &info)
+
+	var got []string
+	for e, effects := range info.HasEffects {
+		if effects {
+			got = append(got, ExprString(e))
+		}
+	}
+	checkSorted(t, got, "<-ch", "f()", "f()", "f() + 2", "int64(len(s) + f())", "len(s)", "len(s)", "len(s) + f()")
+	for e, effects := range info.HasEffects {
+		if s := ExprString(e); (s == "1 + 2" || s == "len(a)") && effects {
+			t.Errorf("%s has effects, want none", s)
+		}
+	}
+}
+
+func TestExplicitZeroFields(t *testing.T) {
+	const src = `package p
+
+type T struct {
+	Count int
+	Name  string
+	On    bool
+	F     float64
+	C     complex128
+	P     *int
+	I     interface{}
+}
+
+var (
+	_ = T{Count: 0, Name: "", On: false, F: 0.0, C: 0i, P: nil}
+	_ = T{Count: 1, Name: "x", On: true, F: 1e-9, C: 1i}
+	_ = T{0, "", false, 0, 0, nil, nil}
+	_ = T{I: 0}
+	_ = T{I: ""}
+)`
+	for _, suggest := range []bool{false, true} {
+		conf := Config{Suggestions: suggest}
+		info := Info{ExplicitZeroFields: make(map[*ast.KeyValueExpr]bool)}
+		if errs := checkWithConfig(t, &conf, src, &info); len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		var got []string
+		for kv := range info.ExplicitZeroFields {
+			got = append(got, ExprString(kv.Key))
+		}
+		sort.Strings(got)
+		var want []string
+		if suggest {
+			want = []string{"C", "Count", "F", "Name", "On"}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Suggestions = %v: got %q, want %q", suggest, got, want)
+		}
+	}
+}
+
+func TestErrorCodeAndTypes(t *testing.T) {
+	for _, test := range []struct {
+		src   string
+		code  string
+		types []string
+	}{
+		{`package p; var x int; var y string; var _ = x + y`, "MismatchedTypes", []string{"int", "string"}},
+		{`package p; var x int; var y string; var _ = x == y`, "MismatchedTypes", []string{"int", "string"}},
+		{`package p; var x, y []int; var _ = x == y`, "UndefinedOp", []string{"[]int", "[]int"}},
+		{`package p; var _ = undefined`, "UndeclaredName", nil},
+	} {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "p.go", test.src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var errs []Error
+		conf := Config{Error: func(err error) { errs = append(errs, err.(Error)) }}
+		conf.Check(f.Name.Name, fset, []*ast.File{f}, nil)
+		if len(errs) == 0 {
+			t.Errorf("%s: no error reported", test.src)
+			continue
+		}
+		e := errs[0]
+		if e.Code() != test.code {
+			t.Errorf("%s: got code %q, want %q", test.src, e.Code(), test.code)
+		}
+		var types []string
+		for _, typ := range e.Types() {
+			types = append(types, typ.String())
+		}
+		if !reflect.DeepEqual(types, test.types) {
+			t.Errorf("%s: got types %v, want %v", test.src, types, test.types)
+		}
+	}
+}
+
+func TestReportSwappedOffsetIndices(t *testing.T) {
+	const src = `package p
+
+func _(s []int, i, j int) {
+	_ = s[i:i+1]
+	_ = s[i-1:i]
+	_ = s[i:j]
+	_ = s[i+1:j]
+	_ = s[i+1:i]
+	_ = s[i:i-1]
+	_ = s[2+i:i+1]
+	_ = s[i:i+2:i+1]
+	_ = s[i:i+1:i+1]
+	_ = s[i*2:i]
+}`
+	for _, report := range []bool{false, true} {
+		var errs []Error
+		conf := Config{
+			Error:                      func(err error) { errs = append(errs, err.(Error)) },
+			ReportSwappedOffsetIndices: report,
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "p.go", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conf.Check(f.Name.Name, fset, []*ast.File{f}, nil)
+
+		var got []string
+		for _, e := range errs {
+			if !e.Soft || e.Code() != "SwappedSliceIndices" {
+				t.Errorf("got %v (soft = %v, code = %q), want soft SwappedSliceIndices error", e, e.Soft, e.Code())
+			}
+			got = append(got, e.Msg)
+		}
+		var want []string
+		if report {
+			want = []string{
+				"swapped slice indices: i + 1 > i",
+				"swapped slice indices: i > i - 1",
+				"swapped slice indices: 2 + i > i + 1",
+				"swapped slice indices: i + 2 > i + 1",
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReportSwappedOffsetIndices = %v: got %q, want %q", report, got, want)
+		}
+	}
+}
+
+func TestOnOverflowCheck(t *testing.T) {
+	const src = `package p
+
+const (
+	a int8 = 100
+	_ = a + 27
+	_ = a + 28
+	_ = 1 << 10
+	_ = 1 << 600 >> 600
+	_ = 1.5 * 2
+)`
+	var got []string
+	conf := Config{
+		OnOverflowCheck: func(e ast.Expr, typ Type, overflowed bool) {
+			got = append(got, fmt.Sprintf("%s: %s %v", ExprString(e), typ, overflowed))
+		},
+	}
+	checkWithConfig(t, &conf, src, nil) // errors are ignored
+	want := []string{
+		"a + 27: int8 false",
+		"a + 28: int8 true",
+		"1 << 10: untyped int false",
+		"1 << 600: untyped int true",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestInferredArrayLens(t *testing.T) {
+	const src = `package p
+
+type A [m]int
+
+var (
+	_ = [n]int{1, 2, 3}
+	_ = [...]int{1, 2}
+	_ = [4]int{1}
+	_ = A{1, 2, 3, 4, 5}
+)`
+	info := Info{
+		Types:             make(map[ast.Expr]TypeAndValue),
+		InferredArrayLens: make(map[*ast.CompositeLit]int64),
+	}
+	checkWithConfig(t, &Config{}, src, &info) // errors are ignored
+
+	var got []string
+	for lit, n := range info.InferredArrayLens {
+		got = append(got, fmt.Sprintf("%s: %d", ExprString(lit.Type), n))
+	}
+	checkSorted(t, got, "A: 5", "[n]int: 3")
+
+	// The recorded literal type has the guessed length.
+	for lit := range info.InferredArrayLens {
+		a, _ := info.Types[lit].Type.Underlying().(*Array)
+		if a == nil || a.Len() != info.InferredArrayLens[lit] {
+			t.Errorf("%s: got type %v", ExprString(lit), info.Types[lit].Type)
+		}
+	}
+}
+
+func TestComparisonConstantSide(t *testing.T) {
+	const src = `package p
+
+const c = 1
+
+func _(x, y int) {
+	_ = x == y
+	_ = 5 == x
+	_ = x < c
+	_ = c != 2
+	_ = x == nil
+}`
+	info := Info{ComparisonConstantSide: make(map[*ast.BinaryExpr]int)}
+	checkWithConfig(t, &Config{}, src, &info) // errors are ignored
+	checkInfoEntries(t, info.ComparisonConstantSide, "5 == x: 1", "c != 2: 3", "x < c: 2", "x == y: 0")
+}
+
+func TestLossyConversions(t *testing.T) {
+	const src = `package p
+
+const c float32 = 1.0 / 3
+
+var (
+	_ = float32(0.1)
+	_ = float32(0.5)
+	_ = float64(1.0 / 10)
+	_ = int32(1 << 10)
+	_ float32 = 0.2
+	_ float32 = 0.25
+	_ = c + 1
+)`
+	info := Info{LossyConversions: make(map[ast.Expr]bool)}
+	mustTypecheck(t, "LossyConversions", src, &info)
+	checkInfoEntries(t, info.LossyConversions, "0.1", "0.2", "1.0 / 10", "1.0 / 3", "c + 1")
+}
+
+func TestShadowedUniverse(t *testing.T) {
+	const src = `package p
+
+type int32 uint
+
+var nil = 0
+
+func _() {
+	true := false
+	if true {
+	}
+	len := func(string) int { return 0 }
+	_ = len("foo")
+	_ = int32(1)
+	_ = nil
+	_ = cap([]int{})
+	var x int
+	_ = x
+}`
+	info := Info{ShadowedUniverse: make(map[*ast.Ident]string)}
+	mustTypecheck(t, "ShadowedUniverse", src, &info)
+	checkInfoEntries(t, info.ShadowedUniverse, "int32: type", "len: builtin function", "nil: nil", "true: constant")
+}
+
+func TestShiftToZero(t *testing.T) {
+	const src = `package p
+
+func _(x uint32, y int8, z int64, s uint) {
+	_ = x << 40
+	_ = x >> 32
+	_ = x << 31
+	_ = y << 8
+	_ = y >> 8
+	_ = z << s
+	_ = uint32(1) >> 33
+	_ = uint32(0) << 32
+	_ = 1 << 100 >> 100
+}`
+	info := Info{ShiftToZero: make(map[*ast.BinaryExpr]bool)}
+	mustTypecheck(t, "ShiftToZero", src, &info)
+	checkInfoEntries(t, info.ShiftToZero, "uint32(0) << 32", "uint32(1) >> 33", "x << 40", "x >> 32", "y << 8")
+}
+
+func TestCommaOkType(t *testing.T) {
+	const src = `package p
+
+func _(m map[string]int, c chan float64, x interface{}, s []int) {
+	_ = m["a"]
+	_, _ = m["b"]
+	_ = <-c
+	_, _ = <-c
+	_ = x.(string)
+	_ = s[0]
+	_ = len(s)
+}`
+	info := Info{Types: make(map[ast.Expr]TypeAndValue)}
+	mustTypecheck(t, "p", src, &info)
+
+	var got []string
+	for e := range info.Types {
+		switch e.(type) {
+		case *ast.IndexExpr, *ast.UnaryExpr, *ast.TypeAssertExpr, *ast.CallExpr:
+			typ, hasOk := CommaOkType(e, &info)
+			got = append(got, fmt.Sprintf("%s: %s %v", ExprString(e), typ, hasOk))
+		}
+	}
+	checkSorted(t, got,
+		`<-c: float64 true`,
+		`<-c: float64 true`,
+		`len(s): int false`,
+		`m["a"]: int true`,
+		`m["b"]: int true`,
+		`s[0]: int false`,
+		`x.(string): string true`,
+	)
+
+	if typ, hasOk := CommaOkType(&ast.Ident{Name: "x"}, &info); typ != nil || hasOk {
+		t.Errorf("got %v, %v for unrecorded expression; want nil, false", typ, hasOk)
+	}
+}
+
+func TestAllowUnexportedFieldLit(t *testing.T) {
+	const libSrc = `package lib; type T struct{ A, b, c int }`
+	const src = `package p; import "lib"; var _ = lib.T{1, 2, 3}`
+
+	fset := token.NewFileSet()
+	imports := make(testImporter)
+	lib, err := pkgFor("lib", libSrc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imports["lib"] = lib
+
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, allow := range []func(*Var, Type) bool{
+		nil,
+		func(field *Var, litType Type) bool {
+			return field.Name() == "b" && litType.String() == "lib.T"
+		},
+	} {
+		var got []string
+		conf := Config{
+			Importer:                imports,
+			Error:                   func(err error) { got = append(got, err.(Error).Msg) },
+			AllowUnexportedFieldLit: allow,
+		}
+		conf.Check(f.Name.Name, fset, []*ast.File{f}, nil)
+		want := []string{
+			"implicit assignment to unexported field b in lib.T literal",
+			"implicit assignment to unexported field c in lib.T literal",
+		}
+		if allow != nil {
+			want = want[1:]
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestTypeAndValueMode(t *testing.T) {
+	const src = `package p
+
+const c = 1
+
+func f() {}
+
+func _(m map[string]int, s []int, x interface{}, ch chan int) {
+	_ = m["a"]
+	_ = s[0]
+	_ = s[0] + 1
+	_ = c
+	_ = x.(int)
+	_ = <-ch
+	_ = len(s)
+	f()
+	_ = []int(nil)
+}`
+	info := Info{Types: make(map[ast.Expr]TypeAndValue)}
+	mustTypecheck(t, "p", src, &info)
+
+	want := map[string]OperandMode{
+		`m["a"]`:   MapIndexMode,
+		`s[0]`:     VariableMode,
+		`s[0] + 1`: ValueMode,
+		`c`:        ConstantMode,
+		`x.(int)`:  CommaOkMode,
+		`<-ch`:     CommaOkMode,
+		`len`:      BuiltinMode,
+		`f()`:      NoValueMode,
+		`[]int`:    TypeMode,
+	}
+	for e, tv := range info.Types {
+		s := ExprString(e)
+		if mode, ok := want[s]; ok {
+			if tv.Mode() != mode {
+				t.Errorf("%s: got mode %s, want %s", s, tv.Mode(), mode)
+			}
+			delete(want, s)
+		}
+	}
+	for s := range want {
+		t.Errorf("%s: no type recorded", s)
+	}
+
+	if got, want := MapIndexMode.String(), "map index expression"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDynamicMapKeys(t *testing.T) {
+	const src = `package p
+
+const c = "c"
+
+func f() string
+
+func _(k string, i interface{}) {
+	_ = map[string]int{"a": 1, c: 2, k: 3, f(): 4, k + c: 5}
+	_ = map[interface{}]int{1: 1, i: 2, [1]int{}: 3}
+}`
+	info := Info{DynamicMapKeys: make(map[ast.Expr]bool)}
+	mustTypecheck(t, "p", src, &info)
+
+	checkInfoEntries(t, info.DynamicMapKeys, "([1]int literal)", "f()", "i", "k", "k + c")
+}
+
+func TestUnsignedNegations(t *testing.T) {
+	const src = `package p
+
+const (
+	_ = -uint8(0)
+	_ = -uint8(5)
+	_ = -int8(5)
+	_ = -5
+	_ = ^uint8(5)
+)`
+	info := Info{UnsignedNegations: make(map[*ast.UnaryExpr]struct{ Operand, Result constant.Value })}
+	checkWithConfig(t, &Config{}, src, &info) // errors are tested in testdata/check/const1.src
+
+	var got []string
+	for e, v := range info.UnsignedNegations {
+		got = append(got, fmt.Sprintf("%s: %s -> %s", ExprString(e), v.Operand, v.Result))
+	}
+	checkSorted(t, got, "-uint8(0): 0 -> 0", "-uint8(5): 5 -> -5")
+}
+
+func TestConstantComparisonValue(t *testing.T) {
+	const src = `package p
+
+const c = 1 < 2
+
+var (
+	_      = 2.5 <= 1
+	_ bool = "a" == "a"
+	_      = c != false
+)
+
+func _() {
+	if 3 > 4 {
+	}
+}`
+	info := Info{Types: make(map[ast.Expr]TypeAndValue)}
+	mustTypecheck(t, "p", src, &info)
+
+	var got []string
+	for e, tv := range info.Types {
+		if b, _ := e.(*ast.BinaryExpr); b != nil {
+			got = append(got, fmt.Sprintf("%s: %s %v", ExprString(b), tv.Type, tv.Value))
+		}
+	}
+	checkSorted(t, got,
+		`"a" == "a": bool true`,
+		`1 < 2: untyped bool true`,
+		`2.5 <= 1: bool false`,
+		`3 > 4: untyped bool false`,
+		`c != false: bool true`,
+	)
+}
+
+func TestOnSizeof(t *testing.T) {
+	const src = `package p
+
+import "unsafe"
+
+const (
+	_ = int(1 << 30)
+	_ = ^uint16(0)
+	_ = unsafe.Sizeof(struct{ a, b int32 }{})
+)`
+	seen := make(map[string]int64)
+	conf := Config{
+		Sizes: SizesFor("gc", "386"),
+		OnSizeof: func(t Type, size int64) {
+			seen[t.String()] = size
+		},
+	}
+	if errs := checkWithConfig(t, &conf, src, nil); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	want := map[string]int64{
+		"int":                      4,
+		"uint":                     4, // shift count 30 converted to uint
+		"uint16":                   2,
+		"struct{a int32; b int32}": 8,
+	}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("got %v, want %v", seen, want)
+	}
+}
+
+func TestRecoverCompositeLitType(t *testing.T) {
+	const src = `package p
+
+func _(a, b, c int) {
+	_ = T{{a, b}, {K: c}}
+}`
+	for _, recover := range []bool{false, true} {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "p.go", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Remove the literal type T, as in {{a, b}, {K: c}}.
+		ast.Inspect(f, func(n ast.Node) bool {
+			if lit, _ := n.(*ast.CompositeLit); lit != nil && lit.Type != nil {
+				lit.Type = nil
+			}
+			return true
+		})
+
+		var errs []string
+		conf := Config{
+			Error:                   func(err error) { errs = append(errs, err.(Error).Msg) },
+			RecoverCompositeLitType: recover,
+		}
+		info := Info{Uses: make(map[*ast.Ident]Object)}
+		conf.Check(f.Name.Name, fset, []*ast.File{f}, &info)
+
+		var used []string
+		for id := range info.Uses {
+			used = append(used, id.Name)
+		}
+		sort.Strings(used)
+		want := []string{"int"}
+		if recover {
+			want = []string{"a", "b", "c", "int"}
+		}
+		if !reflect.DeepEqual(used, want) {
+			t.Errorf("RecoverCompositeLitType = %v: got uses %v, want %v", recover, used, want)
+		}
+		if len(errs) != 1 || errs[0] != "missing type in composite literal" {
+			t.Errorf("RecoverCompositeLitType = %v: got errors %q", recover, errs)
+		}
+	}
+}
+
+func TestOmittedEmbedded(t *testing.T) {
+	const src = `package p
+
+type (
+	A struct{ x int }
+	B struct{ y int }
+	T struct {
+		A
+		*B
+		z int
+	}
+)
+
+var (
+	_ = T{z: 1}
+	_ = T{A: A{}, z: 1}
+	_ = T{A: A{}, B: nil}
+	_ = T{A{}, nil, 0}
+	_ = T{}
+)`
+	info := Info{OmittedEmbedded: make(map[*ast.CompositeLit][]*Var)}
+	mustTypecheck(t, "p", src, &info)
+
+	var got []string
+	for lit, fields := range info.OmittedEmbedded {
+		var names []string
+		for _, f := range fields {
+			names = append(names, f.Name())
+		}
+		got = append(got, fmt.Sprintf("%d elements: %s", len(lit.Elts), strings.Join(names, ", ")))
+	}
+	checkSorted(t, got, "1 elements: A, B", "2 elements: B")
+}
+
+func TestConvertUntyped(t *testing.T) {
+	for _, test := range []struct {
+		val    constant.Value
+		kind   BasicKind
+		target Type
+		want   string // value and type, or error
+	}{
+		{constant.MakeInt64(256), UntypedInt, Typ[Uint8], "error: 256 overflows uint8"},
+		{constant.MakeInt64(256), UntypedInt, Typ[Int], "256 int"},
+		{constant.MakeFloat64(1.5), UntypedFloat, Typ[Float32], "1.5 float32"},
+		{constant.MakeFloat64(1.5), UntypedFloat, Typ[Int], "error: 1.5 truncated to int (would be 1)"},
+		{constant.MakeString("foo"), UntypedString, NewInterfaceType(nil, nil), "\"foo\" string"},
+		{constant.MakeInt64(1), UntypedRune, Typ[UntypedFloat], "1 untyped float"},
+		{nil, UntypedNil, NewSlice(Typ[Int]), "<nil> untyped nil"}, // nil remains untyped,
+		{nil, UntypedNil, Typ[Int], "error: cannot convert untyped nil to int"},
+		{constant.MakeInt64(1), Int, Typ[Int], "error: ConvertUntyped: 2 is not an untyped kind"},
+		{constant.MakeString("x"), UntypedInt, Typ[Int], "error: ConvertUntyped: value kind of \"x\" does not match untyped int"},
+		{constant.MakeFloat64(1.5), UntypedInt, Typ[Int], "error: ConvertUntyped: value kind of 1.5 does not match untyped int"},
+		{constant.MakeInt64(0), UntypedNil, Typ[Int], "error: ConvertUntyped: value kind of 0 does not match untyped nil"},
+	} {
+		var got string
+		val, typ, err := ConvertUntyped(test.val, test.kind, test.target, nil)
+		if err != nil {
+			got = "error: " + err.Error()
+		} else {
+			got = fmt.Sprintf("%v %s", val, typ)
+		}
+		if got != test.want {
+			t.Errorf("ConvertUntyped(%v, %s): got %s, want %s", test.val, test.target, got, test.want)
+		}
+	}
+
+	// int is 32 bits on 386
+	if _, _, err := ConvertUntyped(constant.MakeInt64(1<<31), UntypedInt, Typ[Int], SizesFor("gc", "386")); err == nil {
+		t.Errorf("1 << 31 converted to 32-bit int: got no error")
+	}
+}
+
+func TestAssertTypeExpr(t *testing.T) {
+	const src = `package p
+
+type T struct{}
+
+func (T) m() {}
+
+func _(x interface{}, y interface{ m() }) {
+	_ = x.(int)
+	_, _ = x.([]T)
+	_ = y.(T)
+	_ = y.(interface{ m() })
+	_ = y.(string)
+	_ = x.(undefined)
+	switch x.(type) {
+	}
+}`
+	info := Info{AssertTypeExpr: make(map[*ast.TypeAssertExpr]Type)}
+	checkWithConfig(t, &Config{}, src, &info) // errors are ignored
+	checkInfoEntries(t, info.AssertTypeExpr,
+		"x.([]T): []p.T",
+		"x.(int): int",
+		"y.(T): p.T",
+		"y.(interface{m()}): interface{m()}",
+		"y.(string): string",
+	)
+}
+
+func TestTautologicalComparisons(t *testing.T) {
+	const src = `package p
+
+const c = 3
+
+var (
+	_ = 3 == 3
+	_ = "a" != ("a")
+	_ = 1.0 <= 1
+	_ = 3 == 4
+	_ = c == 3
+	_ = 'a' == 97
+)`
+	for _, suggest := range []bool{false, true} {
+		conf := Config{Suggestions: suggest}
+		info := Info{TautologicalComparisons: make(map[*ast.BinaryExpr]bool)}
+		if errs := checkWithConfig(t, &conf, src, &info); len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		var want []string
+		if suggest {
+			want = []string{`"a" != ("a")`, `'a' == 97`, `1.0 <= 1`, `3 == 3`}
+		}
+		checkInfoEntries(t, info.TautologicalComparisons, want...)
+	}
+}
+
+func TestAssignableConvertibleToSized(t *testing.T) {
+	big := constant.MakeInt64(1 << 40)
+	s64 := SizesFor("gc", "amd64")
+	s32 := SizesFor("gc", "386")
+	for _, test := range []struct {
+		V, T        Type
+		val         constant.Value
+		sizes       Sizes
+		assignable  bool
+		convertible bool
+	}{
+		{Typ[UntypedInt], Typ[Int], big, s64, true, true},
+		{Typ[UntypedInt], Typ[Int], big, s32, false, false},
+		{Typ[UntypedInt], Typ[Int], big, nil, true, true},
+		{Typ[UntypedInt], Typ[Uint], constant.MakeInt64(-1), s64, false, false},
+		{Typ[Int64], Typ[Int], big, s32, false, false},
+		{Typ[Int64], Typ[Int], nil, s32, false, true},
+		{Typ[UntypedInt], Typ[Int32], constant.MakeInt64(1 << 20), s32, true, true},
+		{Typ[UntypedInt], Typ[String], constant.MakeInt64(65), s64, false, true},
+	} {
+		if got := AssignableToSized(test.V, test.T, test.val, test.sizes); got != test.assignable {
+			t.Errorf("AssignableToSized(%s, %s, %v, %v) = %v, want %v", test.V, test.T, test.val, test.sizes, got, test.assignable)
+		}
+		if got := ConvertibleToSized(test.V, test.T, test.val, test.sizes); got != test.convertible {
+			t.Errorf("ConvertibleToSized(%s, %s, %v, %v) = %v, want %v", test.V, test.T, test.val, test.sizes, got, test.convertible)
+		}
+	}
+}
+
+func TestSliceIndexAssumptions(t *testing.T) {
+	const src = `package p
+
+func _(s []int, a [10]int, str string, i int) {
+	_ = s[1:]
+	_ = s[:5]
+	_ = s[i:3]
+	_ = s[0:2:4]
+	_ = s[:]
+	_ = s[i:]
+	_ = a[1:5]
+	_ = str[1:2]
+}`
+	info := Info{SliceIndexAssumptions: make(map[*ast.SliceExpr][]int64)}
+	mustTypecheck(t, "p", src, &info)
+	checkInfoEntries(t, info.SliceIndexAssumptions, "s[0:2:4]: [0 2 4]", "s[1:]: [1]", "s[:5]: [5]", "s[i:3]: [3]")
+}
+
+func TestArithmeticWidening(t *testing.T) {
+	const src = `package p
+
+const (
+	_ = 1 + 2.0
+	_ = 'a' * 2
+	_ = 1.5i - 'b'
+	_ = 1 + 2
+	_ = 1 < 2.5
+	_ = "a" + "b"
+)
+
+var x float32 = 1 + 2.0`
+	info := Info{ArithmeticWidening: make(map[*ast.BinaryExpr]struct{ From, To BasicKind })}
+	mustTypecheck(t, "p", src, &info)
+
+	var got []string
+	for e, w := range info.ArithmeticWidening {
+		got = append(got, fmt.Sprintf("%s: %s -> %s", ExprString(e), Typ[w.From], Typ[w.To]))
+	}
+	checkSorted(t, got,
+		"'a' * 2: untyped int -> untyped rune",
+		"1 + 2.0: untyped int -> untyped float",
+		"1 + 2.0: untyped int -> untyped float",
+		"1 < 2.5: untyped int -> untyped float",
+		"1.5i - 'b': untyped rune -> untyped complex",
+	)
+}
@@ -9,6 +9,8 @@ package types
 
 import (
 	"go/ast"
+	"go/constant"
+	"go/token"
 )
 
 type (
@@ -46,6 +48,51 @@ type Info struct {
 	Selections map[*ast.SelectorExpr]*Selection
 	Scopes     map[ast.Node]*Scope
 	InitOrder  []*Initializer
+
+	ClampedOverflows           map[ast.Expr]bool
+	LitElemCount               map[*ast.CompositeLit]int
+	StringByteIndex            map[*ast.IndexExpr]bool
+	PendingShiftResults        map[ast.Expr]bool
+	LitMaxIndex                map[*ast.CompositeLit]int64
+	UnaryOps                   map[ast.Expr]UnaryOp
+	JSUnsafeIntegers           map[ast.Expr]bool
+	ConstantOrigin             map[ast.Expr]string
+	StaticInBoundsIndex        map[*ast.IndexExpr]bool
+	AlwaysFalsePointerCompare  map[*ast.BinaryExpr]bool
+	AssertInterfaceMethodCount map[*ast.TypeAssertExpr]int
+	BasicKinds                 map[ast.Expr]BasicKind
+	OpPositions                map[ast.Expr]token.Pos
+	NeverMaterialized          map[ast.Expr]bool
+	IsNamedType                map[ast.Expr]bool
+	DivByZeroSites             map[*ast.BinaryExpr]struct {
+		Divisor ast.Expr
+		Val     constant.Value
+	}
+	FinalDefaults           map[ast.Expr]Type
+	PartialStructLits       map[*ast.CompositeLit]int
+	LitTypeAlias            map[*ast.CompositeLit]bool
+	ComparisonKind          map[*ast.BinaryExpr]string
+	IdentityConversions     map[*ast.CallExpr]bool
+	ImplicitConversions     map[ast.Expr]Type
+	BoolInArithmetic        map[ast.Expr]bool
+	MaxLitDepth             map[*ast.CompositeLit]int
+	Untyped                 map[ast.Expr]*Basic
+	ElemPrecisionLoss       map[ast.Expr]struct{ Orig, Rounded constant.Value }
+	StaticValidSlice        map[*ast.SliceExpr]bool
+	HasEffects              map[ast.Expr]bool
+	ExplicitZeroFields      map[*ast.KeyValueExpr]bool
+	InferredArrayLens       map[*ast.CompositeLit]int64
+	ComparisonConstantSide  map[*ast.BinaryExpr]int
+	LossyConversions        map[ast.Expr]bool
+	ShadowedUniverse        map[*ast.Ident]string
+	ShiftToZero             map[*ast.BinaryExpr]bool
+	DynamicMapKeys          map[ast.Expr]bool
+	UnsignedNegations       map[*ast.UnaryExpr]struct{ Operand, Result constant.Value }
+	OmittedEmbedded         map[*ast.CompositeLit][]*Var
+	AssertTypeExpr          map[*ast.TypeAssertExpr]Type
+	TautologicalComparisons map[*ast.BinaryExpr]bool
+	SliceIndexAssumptions   map[*ast.SliceExpr][]int64
+	ArithmeticWidening      map[*ast.BinaryExpr]struct{ From, To BasicKind }
 }
 
 func getInferred(info *Info) map[ast.Expr]_Inferred {
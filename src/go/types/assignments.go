@@ -17,6 +17,12 @@ import (
 // Use T == nil to indicate assignment to an untyped blank identifier.
 // x.mode is set to invalid if the assignment failed.
 func (check *Checker) assignment(x *operand, T Type, context string) {
+	check.assignmentf(x, T, func() string { return context })
+}
+
+// assignmentf is like assignment but the context is only computed, by
+// calling context, if an error is reported.
+func (check *Checker) assignmentf(x *operand, T Type, context func() string) {
 	check.singleValue(x)
 
 	switch x.mode {
@@ -26,7 +32,7 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 		// ok
 	default:
 		// we may get here because of other problems (issue #39634, crash 12)
-		check.errorf(x, 0, "cannot assign %s to %s in %s", x, T, context)
+		check.errorf(x, 0, "cannot assign %s to %s in %s", x, T, context())
 		return
 	}
 
@@ -39,7 +45,7 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 		// complex, or string constant."
 		if T == nil || IsInterface(T) {
 			if T == nil && x.typ == Typ[UntypedNil] {
-				check.errorf(x, _UntypedNil, "use of untyped nil in %s", context)
+				check.errorf(x, _UntypedNil, "use of untyped nil in %s", context())
 				x.mode = invalid
 				return
 			}
@@ -47,23 +53,31 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 		}
 		newType, val, code := check.implicitTypeAndValue(x, target)
 		if code != 0 {
-			msg := check.sprintf("cannot use %s as %s value in %s", x, target, context)
+			msg := check.sprintf("cannot use %s as %s value in %s", x, target, context())
 			switch code {
 			case _TruncatedFloat:
 				msg += " (truncated)"
 			case _NumericOverflow:
-				msg += " (overflows)"
+				if d := check.overflowDetail(x, target); d != "" {
+					msg += " (overflows: " + d + ")"
+				} else {
+					msg += " (overflows)"
+				}
 			default:
 				code = _IncompatibleAssign
 			}
-			check.error(x, code, msg)
+			check.error(x, code, msg+check.overflowSuffix(code))
 			x.mode = invalid
 			return
 		}
 		if val != nil {
+			check.recordLossyConversion(x.expr, x.val, val)
 			x.val = val
 			check.updateExprVal(x.expr, val)
 		}
+		if T != nil && asBasic(T) == nil {
+			check.recordImplicitConversion(x.expr, newType)
+		}
 		if newType != x.typ {
 			x.typ = newType
 			check.updateExprType(x.expr, newType, false)
@@ -72,7 +86,7 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 
 	// A generic (non-instantiated) function value cannot be assigned to a variable.
 	if sig := asSignature(x.typ); sig != nil && len(sig.tparams) > 0 {
-		check.errorf(x, _Todo, "cannot use generic function %s without instantiation in %s", x, context)
+		check.errorf(x, _Todo, "cannot use generic function %s without instantiation in %s", x, context())
 	}
 
 	// spec: "If a left-hand side is the blank identifier, any typed or
@@ -85,9 +99,9 @@ func (check *Checker) assignment(x *operand, T Type, context string) {
 	reason := ""
 	if ok, code := x.assignableTo(check, T, &reason); !ok {
 		if reason != "" {
-			check.errorf(x, code, "cannot use %s as %s value in %s: %s", x, T, context, reason)
+			check.errorf(x, code, "cannot use %s as %s value in %s: %s", x, T, context(), reason)
 		} else {
-			check.errorf(x, code, "cannot use %s as %s value in %s", x, T, context)
+			check.errorf(x, code, "cannot use %s as %s value in %s", x, T, context())
 		}
 		x.mode = invalid
 	}
@@ -114,7 +114,11 @@ func (check *Checker) callExpr(x *operand, call *ast.CallExpr) exprKind {
 						break
 					}
 				}
+				identity := check.conf.Suggestions && !isUntyped(x.typ) && check.identical(x.typ, T)
 				check.conversion(x, T)
+				if identity && x.mode != invalid {
+					check.recordIdentityConversion(call)
+				}
 			}
 		default:
 			check.use(call.Args...)
@@ -129,6 +133,9 @@ func (check *Checker) callExpr(x *operand, call *ast.CallExpr) exprKind {
 			x.mode = invalid
 		}
 		x.expr = call
+		if x.mode == constant_ {
+			check.visitConstBuiltin(call.Fun)
+		}
 		// a non-constant result implies a function call
 		if x.mode != invalid && x.mode != constant_ {
 			check.hasCallOrRecv = true
@@ -435,7 +442,9 @@ func (check *Checker) selector(x *operand, e *ast.SelectorExpr) {
 				x.mode = constant_
 				x.typ = exp.typ
 				x.val = exp.val
+				check.recordConstantOrigin(e, "imported")
 			case *TypeName:
+				check.typeName = exp
 				x.mode = typexpr
 				x.typ = exp.typ
 			case *Var:
@@ -692,6 +701,23 @@ func (check *Checker) use(arg ...ast.Expr) {
 	}
 }
 
+// useLitElts is like use, but for the elements of a composite literal of
+// unknown type. Keys are not "used" as they may be field names, and the
+// elements of nested composite literals with omitted type are "used"
+// recursively rather than reporting the missing literal type again.
+func (check *Checker) useLitElts(elts []ast.Expr) {
+	for _, e := range elts {
+		if kv, _ := e.(*ast.KeyValueExpr); kv != nil {
+			e = kv.Value
+		}
+		if lit, _ := e.(*ast.CompositeLit); lit != nil && lit.Type == nil {
+			check.useLitElts(lit.Elts)
+			continue
+		}
+		check.use(e)
+	}
+}
+
 // useLHS is like use, but doesn't "use" top-level identifiers.
 // It should be called instead of use if the arguments are
 // expressions on the lhs of an assignment.
@@ -52,6 +52,11 @@ type context struct {
 	isPanic       map[*ast.CallExpr]bool // set of panic call expressions (used for termination check)
 	hasLabel      bool                   // set if a function makes use of labels (only ~1% of functions); unused outside functions
 	hasCallOrRecv bool                   // set if an expression contains a function call or channel receive operation
+	constFoldBits int                    // accumulated size of folded integer constants (see Config.ConstFoldBudgetBits)
+	litDepth      int                    // current nesting depth of composite literals (see Info.MaxLitDepth)
+	maxLitDepth   int                    // maximum nesting depth reached in the current outermost composite literal
+	litFieldPath  []string               // field names of enclosing keyed struct literal elements
+	typeName      *TypeName              // type name denoted by the most recently resolved (qualified) type identifier
 }
 
 // lookup looks up name in the current context and returns the matching object, or nil.
@@ -91,6 +96,9 @@ type Checker struct {
 	posMap  map[*Interface][]token.Pos // maps interface types to lists of embedded interface positions
 	typMap  map[string]*Named          // maps an instantiated named type hash to a *Named type
 
+	recordsUntyped  bool // whether the types of untyped expressions are recorded (see recordUntyped)
+	recordsBuiltins bool // whether the signatures of built-in function calls are recorded (see recordBuiltinType)
+
 	// pkgPathMap maps package names to the set of distinct import paths we've
 	// seen for that name, anywhere in the import graph. It is used for
 	// disambiguating package names in error messages.
@@ -141,6 +149,9 @@ func (check *Checker) rememberUntyped(e ast.Expr, lhs bool, mode operandMode, ty
 		check.untyped = m
 	}
 	m[e] = exprInfo{lhs, mode, typ, val}
+	if m := check.Untyped; m != nil {
+		m[e] = typ
+	}
 }
 
 // later pushes f on to the stack of actions that will be processed later;
@@ -194,6 +205,10 @@ func NewChecker(conf *Config, fset *token.FileSet, pkg *Package, info *Info) *Ch
 		impMap:  make(map[importKey]*Package),
 		posMap:  make(map[*Interface][]token.Pos),
 		typMap:  make(map[string]*Named),
+
+		recordsUntyped: debug || info.Types != nil || info.BasicKinds != nil || info.NeverMaterialized != nil ||
+			info.IsNamedType != nil || info.FinalDefaults != nil || conf.ExprVisitor != nil,
+		recordsBuiltins: info.Types != nil || conf.ExprVisitor != nil,
 	}
 }
 
@@ -251,10 +266,15 @@ func (check *Checker) Files(files []*ast.File) error { return check.checkFiles(f
 
 var errBadCgo = errors.New("cannot use FakeImportC and go115UsesCgo together")
 
+var errBadUntypedConstBits = errors.New("MaxUntypedConstBits must be 0 or at least 64")
+
 func (check *Checker) checkFiles(files []*ast.File) (err error) {
 	if check.conf.FakeImportC && check.conf.go115UsesCgo {
 		return errBadCgo
 	}
+	if n := check.conf.MaxUntypedConstBits; n != 0 && n < 64 {
+		return errBadUntypedConstBits
+	}
 
 	defer check.handleBailout(&err)
 
@@ -329,12 +349,12 @@ func (check *Checker) record(x *operand) {
 		// or until the end of type checking
 		check.rememberUntyped(x.expr, false, x.mode, typ.(*Basic), val)
 	} else {
-		check.recordTypeAndValue(x.expr, x.mode, typ, val)
+		check.recordTypeAndValueInfo(x.expr, TypeAndValue{mode: x.mode, Type: typ, Value: val, viaPointer: x.viaPointer})
 	}
 }
 
 func (check *Checker) recordUntyped() {
-	if !debug && check.Types == nil {
+	if !check.recordsUntyped {
 		return // nothing to do
 	}
 
@@ -344,10 +364,25 @@ func (check *Checker) recordUntyped() {
 			unreachable()
 		}
 		check.recordTypeAndValue(x, info.mode, info.typ, info.val)
+		if m := check.NeverMaterialized; m != nil {
+			m[x] = true
+		}
+		if m := check.FinalDefaults; m != nil {
+			if t := Default(info.typ); t != info.typ {
+				m[x] = t
+			}
+		}
 	}
 }
 
 func (check *Checker) recordTypeAndValue(x ast.Expr, mode operandMode, typ Type, val constant.Value) {
+	check.recordTypeAndValueInfo(x, TypeAndValue{mode: mode, Type: typ, Value: val})
+}
+
+// recordTypeAndValueInfo is like recordTypeAndValue but records tv as is,
+// including properties such as TypeAndValue.IndexViaPointer.
+func (check *Checker) recordTypeAndValueInfo(x ast.Expr, tv TypeAndValue) {
+	mode, typ, val := tv.mode, tv.Type, tv.Value
 	assert(x != nil)
 	assert(typ != nil)
 	if mode == invalid {
@@ -360,7 +395,21 @@ func (check *Checker) recordTypeAndValue(x ast.Expr, mode operandMode, typ Type,
 		assert(typ == Typ[Invalid] || is(typ, IsConstType))
 	}
 	if m := check.Types; m != nil {
-		m[x] = TypeAndValue{mode, typ, val}
+		m[x] = tv
+	}
+	// Built-in function names are recorded again with their call-specific
+	// signature (see recordBuiltinType), or reported once their call turns
+	// out to be constant (see visitConstBuiltin); only report that record.
+	if f := check.conf.ExprVisitor; f != nil && (mode != builtin || typ != Typ[Invalid]) {
+		f(x, tv)
+	}
+	if m := check.BasicKinds; m != nil && mode != typexpr && mode != builtin {
+		if t, _ := under(typ).(*Basic); t != nil {
+			m[x] = t.kind
+		}
+	}
+	if m := check.IsNamedType; m != nil && mode != builtin && mode != novalue {
+		m[x] = isNamed(typ)
 	}
 }
 
@@ -382,6 +431,27 @@ func (check *Checker) recordBuiltinType(f ast.Expr, sig *Signature) {
 	}
 }
 
+// visitConstBuiltin reports the built-in f of a call of constant value
+// (e.g., len("abc")) to Config.ExprVisitor. No call-specific signature is
+// recorded for such calls, so f is reported as recorded in Info.Types.
+func (check *Checker) visitConstBuiltin(f ast.Expr) {
+	visit := check.conf.ExprVisitor
+	if visit == nil {
+		return
+	}
+	for {
+		visit(f, TypeAndValue{mode: builtin, Type: Typ[Invalid]})
+		switch p := f.(type) {
+		case *ast.Ident, *ast.SelectorExpr:
+			return // we're done
+		case *ast.ParenExpr:
+			f = p.X
+		default:
+			unreachable()
+		}
+	}
+}
+
 func (check *Checker) recordCommaOkTypes(x ast.Expr, a [2]Type) {
 	assert(x != nil)
 	if a[0] == nil || a[1] == nil {
@@ -416,6 +486,278 @@ func (check *Checker) recordInferred(call ast.Expr, targs []Type, sig *Signature
 	}
 }
 
+func (check *Checker) recordClampedOverflow(x ast.Expr) {
+	if m := check.ClampedOverflows; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordLitElemCount(x *ast.CompositeLit) {
+	if m := check.LitElemCount; m != nil {
+		m[x] = len(x.Elts)
+	}
+}
+
+func (check *Checker) recordStringByteIndex(x *ast.IndexExpr) {
+	if m := check.StringByteIndex; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordPendingShiftResult(x ast.Expr) {
+	if m := check.PendingShiftResults; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordLitMaxIndex(x *ast.CompositeLit, n int64) {
+	if m := check.LitMaxIndex; m != nil {
+		m[x] = n
+	}
+}
+
+func (check *Checker) recordUnaryOp(x ast.Expr, op token.Token, mode operandMode) {
+	if m := check.UnaryOps; m != nil {
+		var res string
+		switch mode {
+		case constant_:
+			res = "constant"
+		case variable:
+			res = "variable"
+		case commaok:
+			res = "commaok"
+		default:
+			res = "value"
+		}
+		m[x] = UnaryOp{op, res}
+	}
+}
+
+// maxSafeJSInt is the largest integer such that it and all smaller
+// non-negative integers are exactly representable as float64 values.
+var maxSafeJSInt = constant.MakeInt64(1<<53 - 1)
+
+func (check *Checker) recordJSUnsafeInteger(x ast.Expr, val constant.Value) {
+	if m := check.JSUnsafeIntegers; m != nil && x != nil {
+		if constant.Sign(val) < 0 {
+			val = constant.UnaryOp(token.SUB, val, 0)
+		}
+		if constant.Compare(val, token.GTR, maxSafeJSInt) {
+			m[x] = true
+		}
+	}
+}
+
+func (check *Checker) recordConstantOrigin(x ast.Expr, origin string) {
+	if m := check.ConstantOrigin; m != nil {
+		m[x] = origin
+	}
+}
+
+func (check *Checker) recordStaticInBoundsIndex(x *ast.IndexExpr) {
+	if m := check.StaticInBoundsIndex; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordAlwaysFalsePointerCompare(x *ast.BinaryExpr) {
+	if m := check.AlwaysFalsePointerCompare; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordComparisonKind(x *ast.BinaryExpr, kind string) {
+	if m := check.ComparisonKind; m != nil {
+		m[x] = kind
+	}
+}
+
+func (check *Checker) recordIdentityConversion(x *ast.CallExpr) {
+	if m := check.IdentityConversions; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordImplicitConversion(x ast.Expr, typ Type) {
+	if m := check.ImplicitConversions; m != nil {
+		m[x] = typ
+	}
+}
+
+func (check *Checker) recordBoolInArithmetic(x ast.Expr) {
+	if m := check.BoolInArithmetic; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordMaxLitDepth(x *ast.CompositeLit, depth int) {
+	if m := check.MaxLitDepth; m != nil {
+		m[x] = depth
+	}
+}
+
+func (check *Checker) recordElemPrecisionLoss(x ast.Expr, orig, rounded constant.Value) {
+	if m := check.ElemPrecisionLoss; m != nil {
+		m[x] = struct{ Orig, Rounded constant.Value }{orig, rounded}
+	}
+}
+
+func (check *Checker) recordStaticValidSlice(x *ast.SliceExpr) {
+	if m := check.StaticValidSlice; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordHasEffects(x ast.Expr, effects bool) {
+	if m := check.HasEffects; m != nil {
+		m[x] = effects
+	}
+}
+
+func (check *Checker) recordExplicitZeroField(x *ast.KeyValueExpr) {
+	if m := check.ExplicitZeroFields; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordInferredArrayLen(x *ast.CompositeLit, n int64) {
+	if m := check.InferredArrayLens; m != nil {
+		m[x] = n
+	}
+}
+
+func (check *Checker) recordComparisonConstantSide(x *ast.BinaryExpr, side int) {
+	if m := check.ComparisonConstantSide; m != nil {
+		m[x] = side
+	}
+}
+
+// recordLossyConversion records x if the converted constant value val
+// differs from the original value orig.
+func (check *Checker) recordLossyConversion(x ast.Expr, orig, val constant.Value) {
+	if m := check.LossyConversions; m != nil {
+		if orig.Kind() != constant.Unknown && val.Kind() != constant.Unknown && !constant.Compare(orig, token.EQL, val) {
+			m[x] = true
+		}
+	}
+}
+
+// recordShadowedUniverse records id, which denotes obj, if obj is not the
+// predeclared object of the same name.
+func (check *Checker) recordShadowedUniverse(id *ast.Ident, obj Object) {
+	if m := check.ShadowedUniverse; m != nil && obj.Parent() != Universe {
+		if u := Universe.Lookup(id.Name); u != nil {
+			m[id] = predeclaredKind(u)
+		}
+	}
+}
+
+func (check *Checker) recordShiftToZero(x ast.Expr) {
+	if m := check.ShiftToZero; m != nil {
+		if b, _ := x.(*ast.BinaryExpr); b != nil {
+			m[b] = true
+		}
+	}
+}
+
+func (check *Checker) recordDynamicMapKey(x ast.Expr) {
+	if m := check.DynamicMapKeys; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordUnsignedNegation(x *ast.UnaryExpr, operand, result constant.Value) {
+	if m := check.UnsignedNegations; m != nil {
+		m[x] = struct{ Operand, Result constant.Value }{operand, result}
+	}
+}
+
+// recordOmittedEmbedded records the embedded fields of fields that
+// are not visited in the keyed struct literal x, if any.
+func (check *Checker) recordOmittedEmbedded(x *ast.CompositeLit, fields []*Var, visited []bool) {
+	if m := check.OmittedEmbedded; m != nil {
+		var omitted []*Var
+		for i, f := range fields {
+			if f.embedded && !visited[i] {
+				omitted = append(omitted, f)
+			}
+		}
+		if omitted != nil {
+			m[x] = omitted
+		}
+	}
+}
+
+func (check *Checker) recordAssertTypeExpr(x *ast.TypeAssertExpr, typ Type) {
+	if m := check.AssertTypeExpr; m != nil {
+		m[x] = typ
+	}
+}
+
+func (check *Checker) recordTautologicalComparison(x *ast.BinaryExpr) {
+	if m := check.TautologicalComparisons; m != nil {
+		m[x] = true
+	}
+}
+
+func (check *Checker) recordSliceIndexAssumptions(x *ast.SliceExpr, indices []int64) {
+	if m := check.SliceIndexAssumptions; m != nil {
+		m[x] = indices
+	}
+}
+
+// recordArithmeticWidening records the widening of an untyped operand of x
+// if the untyped kinds of the operands before conversion (xk, yk) differ
+// from the ones after conversion (x, y).
+func (check *Checker) recordArithmeticWidening(e ast.Expr, xk, yk BasicKind, x, y *operand) {
+	m := check.ArithmeticWidening
+	b, _ := e.(*ast.BinaryExpr)
+	if m == nil || b == nil || !isUntyped(x.typ) || !isUntyped(y.typ) {
+		return
+	}
+	switch {
+	case asBasic(x.typ).kind != xk:
+		m[b] = struct{ From, To BasicKind }{xk, asBasic(x.typ).kind}
+	case asBasic(y.typ).kind != yk:
+		m[b] = struct{ From, To BasicKind }{yk, asBasic(y.typ).kind}
+	}
+}
+
+func (check *Checker) recordAssertInterfaceMethodCount(x *ast.TypeAssertExpr, n int) {
+	if m := check.AssertInterfaceMethodCount; m != nil {
+		m[x] = n
+	}
+}
+
+func (check *Checker) recordOpPosition(x ast.Expr, pos token.Pos) {
+	if m := check.OpPositions; m != nil {
+		m[x] = pos
+	}
+}
+
+func (check *Checker) recordDivByZero(x ast.Expr, divisor *operand) {
+	if m := check.DivByZeroSites; m != nil {
+		if b, _ := x.(*ast.BinaryExpr); b != nil {
+			m[b] = struct {
+				Divisor ast.Expr
+				Val     constant.Value
+			}{divisor.expr, divisor.val}
+		}
+	}
+}
+
+func (check *Checker) recordPartialStructLit(x *ast.CompositeLit, omitted int) {
+	if m := check.PartialStructLits; m != nil {
+		m[x] = omitted
+	}
+}
+
+func (check *Checker) recordLitTypeAlias(x *ast.CompositeLit) {
+	if m := check.LitTypeAlias; m != nil {
+		m[x] = true
+	}
+}
+
 func (check *Checker) recordDef(id *ast.Ident, obj Object) {
 	assert(id != nil)
 	if m := check.Defs; m != nil {
@@ -21,8 +21,10 @@ func (check *Checker) conversion(x *operand, T Type) {
 	switch {
 	case constArg && isConstType(T):
 		// constant conversion
+		orig := x.val
 		switch t := asBasic(T); {
 		case representableConst(x.val, check, t, &x.val):
+			check.recordLossyConversion(x.expr, orig, x.val)
 			ok = true
 		case isInteger(x.typ) && isString(t):
 			codepoint := unicode.ReplacementChar
@@ -71,6 +73,16 @@ func (check *Checker) conversion(x *operand, T Type) {
 	x.typ = T
 }
 
+// convertibleToType reports whether the conversion T(x) is valid,
+// without reporting errors. It applies the same rules as conversion.
+func (check *Checker) convertibleToType(x *operand, T Type) bool {
+	if x.mode == constant_ && isConstType(T) {
+		t := asBasic(T)
+		return representableConst(x.val, check, t, nil) || isInteger(x.typ) && isString(t)
+	}
+	return x.convertibleTo(check, T, nil)
+}
+
 // TODO(gri) convertibleTo checks if T(x) is valid. It assumes that the type
 // of x is fully known, but that's not the case for say string(1<<s + 1.0):
 // Here, the type of 1<<s + 1.0 will be UntypedFloat which will lead to the
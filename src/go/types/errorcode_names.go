@@ -0,0 +1,145 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package types
+
+// errorCodeNames maps error codes to their names as reported by Error.Code:
+// the name of the respective constant without the leading underscore.
+// The names are stable; a code must not be renamed once it is added.
+var errorCodeNames = [...]string{
+	_Test:                     "Test",
+	_BlankPkgName:             "BlankPkgName",
+	_MismatchedPkgName:        "MismatchedPkgName",
+	_InvalidPkgUse:            "InvalidPkgUse",
+	_BadImportPath:            "BadImportPath",
+	_BrokenImport:             "BrokenImport",
+	_ImportCRenamed:           "ImportCRenamed",
+	_UnusedImport:             "UnusedImport",
+	_InvalidInitCycle:         "InvalidInitCycle",
+	_DuplicateDecl:            "DuplicateDecl",
+	_InvalidDeclCycle:         "InvalidDeclCycle",
+	_InvalidTypeCycle:         "InvalidTypeCycle",
+	_InvalidConstInit:         "InvalidConstInit",
+	_InvalidConstVal:          "InvalidConstVal",
+	_InvalidConstType:         "InvalidConstType",
+	_UntypedNil:               "UntypedNil",
+	_WrongAssignCount:         "WrongAssignCount",
+	_UnassignableOperand:      "UnassignableOperand",
+	_NoNewVar:                 "NoNewVar",
+	_MultiValAssignOp:         "MultiValAssignOp",
+	_InvalidIfaceAssign:       "InvalidIfaceAssign",
+	_InvalidChanAssign:        "InvalidChanAssign",
+	_IncompatibleAssign:       "IncompatibleAssign",
+	_UnaddressableFieldAssign: "UnaddressableFieldAssign",
+	_NotAType:                 "NotAType",
+	_InvalidArrayLen:          "InvalidArrayLen",
+	_BlankIfaceMethod:         "BlankIfaceMethod",
+	_IncomparableMapKey:       "IncomparableMapKey",
+	_InvalidIfaceEmbed:        "InvalidIfaceEmbed",
+	_InvalidPtrEmbed:          "InvalidPtrEmbed",
+	_BadRecv:                  "BadRecv",
+	_InvalidRecv:              "InvalidRecv",
+	_DuplicateFieldAndMethod:  "DuplicateFieldAndMethod",
+	_DuplicateMethod:          "DuplicateMethod",
+	_InvalidBlank:             "InvalidBlank",
+	_InvalidIota:              "InvalidIota",
+	_MissingInitBody:          "MissingInitBody",
+	_InvalidInitSig:           "InvalidInitSig",
+	_InvalidInitDecl:          "InvalidInitDecl",
+	_InvalidMainDecl:          "InvalidMainDecl",
+	_TooManyValues:            "TooManyValues",
+	_NotAnExpr:                "NotAnExpr",
+	_TruncatedFloat:           "TruncatedFloat",
+	_NumericOverflow:          "NumericOverflow",
+	_UndefinedOp:              "UndefinedOp",
+	_MismatchedTypes:          "MismatchedTypes",
+	_DivByZero:                "DivByZero",
+	_NonNumericIncDec:         "NonNumericIncDec",
+	_UnaddressableOperand:     "UnaddressableOperand",
+	_InvalidIndirection:       "InvalidIndirection",
+	_NonIndexableOperand:      "NonIndexableOperand",
+	_InvalidIndex:             "InvalidIndex",
+	_SwappedSliceIndices:      "SwappedSliceIndices",
+	_NonSliceableOperand:      "NonSliceableOperand",
+	_InvalidSliceExpr:         "InvalidSliceExpr",
+	_InvalidShiftCount:        "InvalidShiftCount",
+	_InvalidShiftOperand:      "InvalidShiftOperand",
+	_InvalidReceive:           "InvalidReceive",
+	_InvalidSend:              "InvalidSend",
+	_DuplicateLitKey:          "DuplicateLitKey",
+	_MissingLitKey:            "MissingLitKey",
+	_InvalidLitIndex:          "InvalidLitIndex",
+	_OversizeArrayLit:         "OversizeArrayLit",
+	_MixedStructLit:           "MixedStructLit",
+	_InvalidStructLit:         "InvalidStructLit",
+	_MissingLitField:          "MissingLitField",
+	_DuplicateLitField:        "DuplicateLitField",
+	_UnexportedLitField:       "UnexportedLitField",
+	_InvalidLitField:          "InvalidLitField",
+	_UntypedLit:               "UntypedLit",
+	_InvalidLit:               "InvalidLit",
+	_AmbiguousSelector:        "AmbiguousSelector",
+	_UndeclaredImportedName:   "UndeclaredImportedName",
+	_UnexportedName:           "UnexportedName",
+	_UndeclaredName:           "UndeclaredName",
+	_MissingFieldOrMethod:     "MissingFieldOrMethod",
+	_BadDotDotDotSyntax:       "BadDotDotDotSyntax",
+	_NonVariadicDotDotDot:     "NonVariadicDotDotDot",
+	_MisplacedDotDotDot:       "MisplacedDotDotDot",
+	_InvalidDotDotDot:         "InvalidDotDotDot",
+	_UncalledBuiltin:          "UncalledBuiltin",
+	_InvalidAppend:            "InvalidAppend",
+	_InvalidCap:               "InvalidCap",
+	_InvalidClose:             "InvalidClose",
+	_InvalidCopy:              "InvalidCopy",
+	_InvalidComplex:           "InvalidComplex",
+	_InvalidDelete:            "InvalidDelete",
+	_InvalidImag:              "InvalidImag",
+	_InvalidLen:               "InvalidLen",
+	_SwappedMakeArgs:          "SwappedMakeArgs",
+	_InvalidMake:              "InvalidMake",
+	_InvalidReal:              "InvalidReal",
+	_InvalidAssert:            "InvalidAssert",
+	_ImpossibleAssert:         "ImpossibleAssert",
+	_InvalidConversion:        "InvalidConversion",
+	_InvalidUntypedConversion: "InvalidUntypedConversion",
+	_BadOffsetofSyntax:        "BadOffsetofSyntax",
+	_InvalidOffsetof:          "InvalidOffsetof",
+	_UnusedExpr:               "UnusedExpr",
+	_UnusedVar:                "UnusedVar",
+	_MissingReturn:            "MissingReturn",
+	_WrongResultCount:         "WrongResultCount",
+	_OutOfScopeResult:         "OutOfScopeResult",
+	_InvalidCond:              "InvalidCond",
+	_InvalidPostDecl:          "InvalidPostDecl",
+	_InvalidIterVar:           "InvalidIterVar",
+	_InvalidRangeExpr:         "InvalidRangeExpr",
+	_MisplacedBreak:           "MisplacedBreak",
+	_MisplacedContinue:        "MisplacedContinue",
+	_MisplacedFallthrough:     "MisplacedFallthrough",
+	_DuplicateCase:            "DuplicateCase",
+	_DuplicateDefault:         "DuplicateDefault",
+	_BadTypeKeyword:           "BadTypeKeyword",
+	_InvalidTypeSwitch:        "InvalidTypeSwitch",
+	_InvalidExprSwitch:        "InvalidExprSwitch",
+	_InvalidSelectCase:        "InvalidSelectCase",
+	_UndeclaredLabel:          "UndeclaredLabel",
+	_DuplicateLabel:           "DuplicateLabel",
+	_MisplacedLabel:           "MisplacedLabel",
+	_UnusedLabel:              "UnusedLabel",
+	_JumpOverDecl:             "JumpOverDecl",
+	_JumpIntoBlock:            "JumpIntoBlock",
+	_InvalidMethodExpr:        "InvalidMethodExpr",
+	_WrongArgCount:            "WrongArgCount",
+	_InvalidCall:              "InvalidCall",
+	_UnusedResults:            "UnusedResults",
+	_InvalidDefer:             "InvalidDefer",
+	_InvalidGo:                "InvalidGo",
+	_BadDecl:                  "BadDecl",
+	_RepeatedDecl:             "RepeatedDecl",
+	_InvalidUnsafeAdd:         "InvalidUnsafeAdd",
+	_InvalidUnsafeSlice:       "InvalidUnsafeSlice",
+	_InvalidNilCompare:        "InvalidNilCompare",
+	_Todo:                     "Todo",
+}
@@ -1310,6 +1310,14 @@ const (
 	//  var _ = unsafe.Slice(&x, uint64(1) << 63)
 	_InvalidUnsafeSlice
 
+	// _InvalidNilCompare occurs when a value of a type that cannot be nil is
+	// compared to nil.
+	//
+	// Example:
+	//  var x int
+	//  var _ = x == nil
+	_InvalidNilCompare
+
 	// _Todo is a placeholder for error codes that have not been decided.
 	// TODO(rFindley) remove this error code after deciding on errors for generics code.
 	_Todo
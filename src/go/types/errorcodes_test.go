@@ -36,6 +36,9 @@ func TestErrorCodeExamples(t *testing.T) {
 				if got := readCode(typerr); got != value {
 					t.Errorf("%s: example #%d returned code %d (%s), want %d", name, i, got, err, value)
 				}
+				if got, want := typerr.Code(), name[1:]; got != want {
+					t.Errorf("%s: example #%d returned code name %q, want %q", name, i, got, want)
+				}
 			}
 		})
 	})
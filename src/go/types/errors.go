@@ -181,6 +181,14 @@ func (check *Checker) softErrorf(at positioner, code errorCode, format string, a
 	check.err(check.newErrorf(at, code, true, format, args...))
 }
 
+// typedErrorf is like errorf but also records the operand types
+// involved in the error, as reported by Error.Types.
+func (check *Checker) typedErrorf(at positioner, code errorCode, types []Type, format string, args ...interface{}) {
+	err := check.newErrorf(at, code, false, format, args...).(Error)
+	err.types = types
+	check.err(err)
+}
+
 func (check *Checker) invalidAST(at positioner, format string, args ...interface{}) {
 	check.errorf(at, 0, "invalid AST: "+format, args...)
 }
@@ -5,8 +5,10 @@
 package types
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/parser"
 	"go/token"
 )
@@ -97,3 +99,113 @@ func CheckExpr(fset *token.FileSet, pkg *Package, pos token.Pos, expr ast.Expr,
 
 	return nil
 }
+
+// EvalBasicLit returns the constant value and the (untyped) type of the
+// basic literal with the given kind and literal value, as the type checker
+// determines them for an *ast.BasicLit. The value must be a syntactically
+// valid literal of the given kind. If the literal doesn't denote a valid
+// constant (because of number under- or overflow, or because it is
+// excessively long), the result is an error.
+func EvalBasicLit(kind token.Token, value string) (constant.Value, Type, error) {
+	switch kind {
+	case token.INT, token.FLOAT, token.IMAG, token.CHAR, token.STRING:
+		// ok
+	default:
+		return nil, nil, fmt.Errorf("invalid literal kind %s", kind)
+	}
+	var x operand
+	if msg := x.setBasicLit(kind, value); msg != "" {
+		return nil, nil, errors.New(msg)
+	}
+	return x.val, x.typ, nil
+}
+
+// CheckExprConvertibleTo type checks the expression expr as if it had
+// appeared in the given scope and reports, for each of the targets,
+// whether the expression may be explicitly converted to that type
+// (as in T(expr)). The result of type-checking expr itself is returned
+// in checked. If scope is nil, the Universe scope is used.
+//
+// The expression is type-checked once; the conversion rules applied to
+// each target are the same as for conversions in source code. If expr
+// cannot be type-checked, err is the first error encountered, and no
+// target is reported as convertible. Since no file set is provided,
+// error positions are not meaningful beyond their token.Pos values.
+//
+func CheckExprConvertibleTo(expr ast.Expr, scope *Scope, targets []Type) (checked TypeAndValue, convertible []bool, err error) {
+	info := &Info{
+		Types: make(map[ast.Expr]TypeAndValue),
+	}
+	convertible = make([]bool, len(targets))
+	err = checkExprInScope(expr, scope, info, func(check *Checker, x *operand) {
+		if x.mode == invalid {
+			return
+		}
+		for i, T := range targets {
+			y := *x // convertibleToType may modify its operand
+			convertible[i] = check.convertibleToType(&y, T)
+		}
+	})
+	return info.Types[expr], convertible, err
+}
+
+// CheckExprFull type checks the expression expr as if it had appeared
+// in the given scope, and converts the result to the target type as in
+// the conversion target(expr). If scope is nil, the Universe scope is
+// used.
+//
+// The returned tv describes the converted value. If expr is constant,
+// the converted value in tv.Value may have been rounded to fit target
+// (for instance, for floating-point targets); the value before rounding
+// is returned in unrounded. For non-constant expressions, unrounded is
+// nil. As with CheckExprConvertibleTo, error positions are not meaningful
+// beyond their token.Pos values.
+//
+func CheckExprFull(expr ast.Expr, scope *Scope, target Type) (tv TypeAndValue, unrounded constant.Value, err error) {
+	err = checkExprInScope(expr, scope, nil, func(check *Checker, x *operand) {
+		if x.mode == invalid {
+			return
+		}
+		if x.mode == constant_ {
+			unrounded = x.val
+		}
+		check.conversion(x, target)
+		if x.mode == invalid {
+			return
+		}
+		tv.mode = x.mode
+		tv.Type = x.typ
+		if x.mode == constant_ {
+			tv.Value = x.val
+		}
+	})
+	if err != nil {
+		return TypeAndValue{}, nil, err
+	}
+	return tv, unrounded, nil
+}
+
+// checkExprInScope type checks the expression expr as if it had appeared
+// in the given scope (or the Universe scope, if scope is nil), recording
+// type information in info. If the expression was type-checked without
+// errors, f is called with the checker and the resulting operand before
+// the types of untyped (sub-)expressions are recorded.
+func checkExprInScope(expr ast.Expr, scope *Scope, info *Info, f func(check *Checker, x *operand)) (err error) {
+	if scope == nil {
+		scope = Universe
+	}
+
+	// initialize checker
+	check := NewChecker(nil, token.NewFileSet(), nil, info)
+	check.scope = scope
+	defer check.handleBailout(&err)
+
+	// evaluate node
+	var x operand
+	check.expr(&x, expr)
+	f(check, &x)
+	check.processDelayed(0) // incl. all functions
+	check.recordUntyped()
+
+	return nil
+}
@@ -9,10 +9,12 @@ package types_test
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/importer"
 	"go/parser"
 	"go/token"
 	"internal/testenv"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -295,3 +297,135 @@ func f(a int, s string) S {
 		}
 	}
 }
+
+func TestCheckExprConvertibleTo(t *testing.T) {
+	const src = `package p; var x int32; type T []byte`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var conf Config
+	pkg, err := conf.Check("p", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	T := pkg.Scope().Lookup("T").Type()
+
+	targets := []Type{Typ[Int8], Typ[Float32], Typ[String], T}
+	for _, test := range []struct {
+		expr string
+		typ  string
+		want []bool
+	}{
+		{"1.5", "untyped float", []bool{false, true, false, false}},
+		{"300", "untyped int", []bool{false, true, true, false}},
+		{`"foo"`, "untyped string", []bool{false, false, true, true}},
+		{"x", "int32", []bool{true, true, true, false}},
+		{"x == 0", "untyped bool", []bool{false, false, false, false}},
+	} {
+		expr, err := parser.ParseExpr(test.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tv, got, err := CheckExprConvertibleTo(expr, pkg.Scope(), targets)
+		if err != nil {
+			t.Errorf("%s: %v", test.expr, err)
+			continue
+		}
+		if tv.Type.String() != test.typ {
+			t.Errorf("%s: got type %s, want %s", test.expr, tv.Type, test.typ)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.expr, got, test.want)
+		}
+	}
+
+	expr, _ := parser.ParseExpr("undefined")
+	if _, got, err := CheckExprConvertibleTo(expr, nil, targets); err == nil || len(got) != len(targets) {
+		t.Errorf("undefined: got (%v, %v), want error", got, err)
+	}
+}
+
+func TestCheckExprFull(t *testing.T) {
+	for _, test := range []struct {
+		expr    string
+		target  Type
+		val     string // converted value; empty if not constant
+		rounded bool   // converted value differs from unrounded value
+	}{
+		{"0.1", Typ[Float32], "0.1", true},
+		{"0.1", Typ[Float64], "0.1", true},
+		{"0.5", Typ[Float32], "0.5", false},
+		{"1.0 / 3", Typ[Float64], "0.333333", true},
+		{"1 << 10", Typ[Int16], "1024", false},
+		{"len([]int{})", Typ[Float64], "", false},
+	} {
+		expr, err := parser.ParseExpr(test.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tv, unrounded, err := CheckExprFull(expr, nil, test.target)
+		if err != nil {
+			t.Errorf("%s: %v", test.expr, err)
+			continue
+		}
+		if tv.Type != test.target {
+			t.Errorf("%s: got type %s, want %s", test.expr, tv.Type, test.target)
+		}
+		if (tv.Value == nil) != (unrounded == nil) {
+			t.Errorf("%s: got value %v, unrounded value %v", test.expr, tv.Value, unrounded)
+			continue
+		}
+		if tv.Value == nil {
+			if test.val != "" {
+				t.Errorf("%s: got no value, want %s", test.expr, test.val)
+			}
+			continue
+		}
+		if got := tv.Value.String(); got != test.val {
+			t.Errorf("%s: got value %s, want %s", test.expr, got, test.val)
+		}
+		if got := !constant.Compare(tv.Value, token.EQL, unrounded); got != test.rounded {
+			t.Errorf("%s: got rounded = %t, want %t", test.expr, got, test.rounded)
+		}
+	}
+
+	expr, _ := parser.ParseExpr("1.5")
+	if _, _, err := CheckExprFull(expr, nil, Typ[Int]); err == nil {
+		t.Errorf("1.5: got no error for conversion to int")
+	}
+}
+
+func TestEvalBasicLit(t *testing.T) {
+	for _, test := range []struct {
+		kind token.Token
+		lit  string
+		typ  Type
+		val  string // or error, if typ is nil
+	}{
+		{token.INT, "0x_1f", Typ[UntypedInt], "31"},
+		{token.FLOAT, "1e3", Typ[UntypedFloat], "1000"},
+		{token.IMAG, "2i", Typ[UntypedComplex], "(0 + 2i)"},
+		{token.CHAR, "'a'", Typ[UntypedRune], "97"},
+		{token.STRING, "`foo`", Typ[UntypedString], `"foo"`},
+		{token.FLOAT, "1e1000000000", nil, "malformed constant: 1e1000000000"},
+		{token.INT, "1" + strings.Repeat("0", 10000), nil, "excessively long constant: 1000000000... (10001 chars)"},
+		{token.IDENT, "x", nil, "invalid literal kind IDENT"},
+	} {
+		val, typ, err := EvalBasicLit(test.kind, test.lit)
+		if test.typ == nil {
+			if err == nil || err.Error() != test.val {
+				t.Errorf("%s: got error %v, want %s", test.lit, err, test.val)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.lit, err)
+			continue
+		}
+		if typ != test.typ || val.String() != test.val {
+			t.Errorf("%s: got %s, %s; want %s, %s", test.lit, val, typ, test.val, test.typ)
+		}
+	}
+}
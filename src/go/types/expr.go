@@ -13,6 +13,8 @@ import (
 	"go/internal/typeparams"
 	"go/token"
 	"math"
+	"math/big"
+	"strings"
 )
 
 /*
@@ -71,9 +73,13 @@ func init() {
 	}
 }
 
-func (check *Checker) op(m opPredicates, x *operand, op token.Token) bool {
+func (check *Checker) op(m opPredicates, x *operand, op token.Token, unary bool) bool {
 	if pred := m[op]; pred != nil {
 		if !pred(x.typ) {
+			if hint := check.opSuggestion(op, x.typ, unary); hint != "" {
+				check.invalidOp(x, _UndefinedOp, "operator %s not defined for %s (%s)", op, x, hint)
+				return false
+			}
 			check.invalidOp(x, _UndefinedOp, "operator %s not defined for %s", op, x)
 			return false
 		}
@@ -84,6 +90,75 @@ func (check *Checker) op(m opPredicates, x *operand, op token.Token) bool {
 	return true
 }
 
+// An opClass classifies basic types for the purpose of operator suggestions.
+type opClass int
+
+const (
+	noClass opClass = iota
+	boolClass
+	intClass
+	floatClass
+	complexClass
+	stringClass
+)
+
+// opClassOf returns the opClass of typ, or noClass.
+func opClassOf(typ Type) opClass {
+	switch {
+	case isBoolean(typ):
+		return boolClass
+	case isInteger(typ):
+		return intClass
+	case isFloat(typ):
+		return floatClass
+	case isComplex(typ):
+		return complexClass
+	case isString(typ):
+		return stringClass
+	}
+	return noClass
+}
+
+// An opKey identifies a unary or binary operator applied to an operand
+// of a given class.
+type opKey struct {
+	op    token.Token
+	class opClass
+	unary bool
+}
+
+// opSuggestions maps an operator and the class of an operand type for which
+// the operator is not defined to a suggestion for a valid alternative.
+var opSuggestions = map[opKey]string{
+	{token.REM, floatClass, false}:     "use math.Mod, or convert to an integer type",
+	{token.REM, complexClass, false}:   "convert to an integer type",
+	{token.AND, floatClass, false}:     "convert to an integer type",
+	{token.OR, floatClass, false}:      "convert to an integer type",
+	{token.XOR, floatClass, false}:     "convert to an integer type",
+	{token.AND_NOT, floatClass, false}: "convert to an integer type",
+	{token.AND, boolClass, false}:      "use && instead",
+	{token.OR, boolClass, false}:       "use || instead",
+	{token.XOR, boolClass, false}:      "use != for exclusive or",
+	{token.XOR, boolClass, true}:       "use ! for negation",
+	{token.NOT, intClass, true}:        "use ^ for bitwise complement, or compare with 0",
+	{token.LAND, intClass, false}:      "use & for bitwise and, or compare with 0",
+	{token.LOR, intClass, false}:       "use | for bitwise or, or compare with 0",
+	{token.SUB, stringClass, false}:    "use strings.TrimSuffix or strings.ReplaceAll",
+	{token.ADD, boolClass, false}:      "use || instead",
+	{token.MUL, boolClass, false}:      "use && instead",
+}
+
+// opSuggestion returns a suggestion for an alternative to the unary or
+// binary operator op, which is not defined for operands of type typ, or
+// the empty string. It always returns the empty string if Config.Suggestions
+// is not set.
+func (check *Checker) opSuggestion(op token.Token, typ Type, unary bool) string {
+	if !check.conf.Suggestions {
+		return ""
+	}
+	return opSuggestions[opKey{op, opClassOf(typ), unary}]
+}
+
 // overflow checks that the constant x is representable by its type.
 // For untyped constants, it checks that the value doesn't become
 // arbitrarily large.
@@ -91,9 +166,20 @@ func (check *Checker) overflow(x *operand, op token.Token, opPos token.Pos) {
 	assert(x.mode == constant_)
 
 	if x.val.Kind() == constant.Unknown {
-		// TODO(gri) We should report exactly what went wrong. At the
-		//           moment we don't have the (go/constant) API for that.
+		// Operations on unknown operands are not folded, and malformed
+		// constant literals are reported when the literal is checked.
+		// Thus an unknown result was produced by the operation itself:
+		// go/constant returns an unknown value if the magnitude of a
+		// floating-point result exceeds the range of big.Float.
+		// TODO(gri) We should report exactly what went wrong for the
+		//           remaining cases. At the moment we don't have the
+		//           (go/constant) API for that.
 		//           See also TODO in go/constant/value.go.
+		check.overflowChecked(x.expr, x.typ, true)
+		if name := opName(x.expr); name != "" {
+			check.errorf(atPos(opPos), _InvalidConstVal, "constant result overflowed during %s", name)
+			return
+		}
 		check.errorf(atPos(opPos), _InvalidConstVal, "constant result is not representable")
 		return
 	}
@@ -101,14 +187,53 @@ func (check *Checker) overflow(x *operand, op token.Token, opPos token.Pos) {
 	// Typed constants must be representable in
 	// their type after each constant operation.
 	if isTyped(x.typ) {
-		check.representable(x, asBasic(x.typ))
+		typ := x.typ
+		check.representable(x, asBasic(typ))
+		check.overflowChecked(x.expr, typ, x.mode == invalid)
 		return
 	}
 
 	// Untyped integer values must not grow arbitrarily.
-	const prec = 512 // 512 is the constant precision
-	if x.val.Kind() == constant.Int && constant.BitLen(x.val) > prec {
-		check.errorf(atPos(opPos), _InvalidConstVal, "constant %s overflow", opName(x.expr))
+	if x.val.Kind() == constant.Int {
+		prec := 512 // 512 is the default constant precision
+		if n := check.conf.MaxUntypedConstBits; n > 0 {
+			prec = n
+		}
+		overflowed := constant.BitLen(x.val) > prec
+		check.overflowChecked(x.expr, x.typ, overflowed)
+		if overflowed {
+			check.errorf(atPos(opPos), _InvalidConstVal, "constant %s overflow", opName(x.expr))
+			x.val = constant.MakeUnknown()
+			if x.expr != nil {
+				check.recordClampedOverflow(x.expr)
+			}
+		}
+	}
+}
+
+// overflowChecked reports the result of an overflow check of the constant
+// expression e of type typ to Config.OnOverflowCheck, if set.
+func (check *Checker) overflowChecked(e ast.Expr, typ Type, overflowed bool) {
+	if f := check.conf.OnOverflowCheck; f != nil {
+		f(e, typ, overflowed)
+	}
+}
+
+// chargeConstFold charges the size of the folded integer constant x against
+// the per-declaration budget Config.ConstFoldBudgetBits. Once the budget is
+// exceeded, an error is reported (once) and the value of x (and thus of
+// all enclosing constant expressions) becomes unknown.
+func (check *Checker) chargeConstFold(x *operand, opPos token.Pos) {
+	budget := check.conf.ConstFoldBudgetBits
+	if budget <= 0 || x.val.Kind() != constant.Int {
+		return
+	}
+	exceeded := check.constFoldBits > budget
+	check.constFoldBits += constant.BitLen(x.val)
+	if check.constFoldBits > budget {
+		if !exceeded {
+			check.errorf(atPos(opPos), _InvalidConstVal, "constant expression exceeds folding budget of %d bits", budget)
+		}
 		x.val = constant.MakeUnknown()
 	}
 }
@@ -132,6 +257,7 @@ func opName(e ast.Expr) string {
 }
 
 var op2str1 = [...]string{
+	token.SUB: "negation",
 	token.XOR: "bitwise complement",
 }
 
@@ -142,6 +268,7 @@ var op2str2 = [...]string{
 	token.XOR: "bitwise XOR",
 	token.MUL: "multiplication",
 	token.SHL: "shift",
+	token.QUO: "division",
 }
 
 // The unary expression e may be nil. It's passed in for better error messages only.
@@ -150,11 +277,17 @@ func (check *Checker) unary(x *operand, e *ast.UnaryExpr) {
 	if x.mode == invalid {
 		return
 	}
+	check.recordOpPosition(e, e.OpPos)
 	switch e.Op {
 	case token.AND:
 		// spec: "As an exception to the addressability
 		// requirement x may also be a composite literal."
 		if _, ok := unparen(e.X).(*ast.CompositeLit); !ok && x.mode != variable {
+			if x.mode == mapindex {
+				check.invalidOp(x, _UnaddressableOperand, "cannot take address of map element %s (map values are not addressable)", x.expr)
+				x.mode = invalid
+				return
+			}
 			check.invalidOp(x, _UnaddressableOperand, "cannot take address of %s", x)
 			x.mode = invalid
 			return
@@ -164,14 +297,8 @@ func (check *Checker) unary(x *operand, e *ast.UnaryExpr) {
 		return
 
 	case token.ARROW:
-		typ := asChan(x.typ)
+		typ := check.recvChan(x)
 		if typ == nil {
-			check.invalidOp(x, _InvalidReceive, "cannot receive from non-channel %s", x)
-			x.mode = invalid
-			return
-		}
-		if typ.dir == SendOnly {
-			check.invalidOp(x, _InvalidReceive, "cannot receive from send-only channel %s", x)
 			x.mode = invalid
 			return
 		}
@@ -181,7 +308,7 @@ func (check *Checker) unary(x *operand, e *ast.UnaryExpr) {
 		return
 	}
 
-	if !check.op(unaryOpPredicates, x, e.Op) {
+	if check.boolArithmetic(e, e.Op, x) || !check.op(unaryOpPredicates, x, e.Op, true) {
 		x.mode = invalid
 		return
 	}
@@ -195,9 +322,16 @@ func (check *Checker) unary(x *operand, e *ast.UnaryExpr) {
 		if isUnsigned(x.typ) {
 			prec = uint(check.conf.sizeof(x.typ) * 8)
 		}
+		operand := x.val
 		x.val = constant.UnaryOp(e.Op, x.val, prec)
+		if e.Op == token.SUB && isUnsigned(x.typ) {
+			check.recordUnsignedNegation(e, operand, x.val)
+		}
 		x.expr = e
 		check.overflow(x, e.Op, x.Pos())
+		if x.mode == constant_ {
+			check.recordConstantOrigin(e, "folded-unary")
+		}
 		return
 	}
 
@@ -205,6 +339,26 @@ func (check *Checker) unary(x *operand, e *ast.UnaryExpr) {
 	// x.typ remains unchanged
 }
 
+// recvChan returns the channel type of the operand x of a receive
+// operation. If x is not a channel that permits receiving, an error
+// is reported and the result is nil.
+func (check *Checker) recvChan(x *operand) *Chan {
+	if x.isNil() {
+		check.invalidOp(x, _InvalidReceive, "cannot receive from nil")
+		return nil
+	}
+	typ := asChan(x.typ)
+	if typ == nil {
+		check.invalidOp(x, _InvalidReceive, "cannot receive from non-channel %s", x)
+		return nil
+	}
+	if typ.dir == SendOnly {
+		check.invalidOp(x, _InvalidReceive, "cannot receive from send-only channel %s", x)
+		return nil
+	}
+	return typ
+}
+
 func isShift(op token.Token) bool {
 	return op == token.SHL || op == token.SHR
 }
@@ -406,6 +560,7 @@ func (check *Checker) representable(x *operand, typ *Basic) {
 		return
 	}
 	assert(v != nil)
+	check.recordLossyConversion(x.expr, x.val, v)
 	x.val = v
 }
 
@@ -426,6 +581,11 @@ func (check *Checker) representation(x *operand, typ *Basic) (constant.Value, er
 			// float   -> float   : overflows
 			//
 			if !isInteger(x.typ) && isInteger(typ) {
+				// If the value doesn't fit even when truncated,
+				// report the overflow rather than the truncation.
+				if t := truncatedInt(x.val); t != nil && !representableConst(t, check, typ, nil) {
+					return nil, _NumericOverflow
+				}
 				return nil, _TruncatedFloat
 			} else {
 				return nil, _NumericOverflow
@@ -433,18 +593,113 @@ func (check *Checker) representation(x *operand, typ *Basic) (constant.Value, er
 		}
 		return nil, _InvalidConstVal
 	}
+	if isInteger(typ) && isTyped(typ) {
+		check.recordJSUnsafeInteger(x.expr, v)
+	}
 	return v, 0
 }
 
+// truncatedInt returns the integer value of the numeric constant x
+// truncated towards zero, or nil if x has no real-valued representation.
+// If the magnitude of x is too large to be represented by an integer type,
+// the result may be any integer value that is also too large.
+func truncatedInt(x constant.Value) constant.Value {
+	switch v := constant.Val(constant.ToFloat(x)).(type) {
+	case *big.Rat:
+		return constant.Make(new(big.Int).Quo(v.Num(), v.Denom()))
+	case *big.Float:
+		if v.MantExp(nil) > 512 {
+			// Avoid materializing huge integers: use a value
+			// that is too large for any integer type instead.
+			return constant.Shift(constant.MakeInt64(int64(v.Sign())), token.SHL, 513)
+		}
+		if i, _ := v.Int(nil); i != nil {
+			return constant.Make(i)
+		}
+	}
+	return nil
+}
+
 func (check *Checker) invalidConversion(code errorCode, x *operand, target Type) {
 	msg := "cannot convert %s to %s"
 	switch code {
 	case _TruncatedFloat:
 		msg = "%s truncated to %s"
+		if x.mode == constant_ {
+			if t := truncatedInt(x.val); t != nil {
+				msg += " (would be " + t.String() + ")"
+			}
+		}
 	case _NumericOverflow:
 		msg = "%s overflows %s"
+		if d := check.overflowDetail(x, target); d != "" {
+			msg += " (" + d + ")"
+		}
 	}
-	check.errorf(x, code, msg, x, target)
+	check.errorf(x, code, msg+check.overflowSuffix(code), x, target)
+}
+
+// overflowDetail describes the innermost operation of the untyped constant
+// expression x whose result is not representable as a value of type target,
+// if it is not the operation producing x itself. Otherwise the result is "".
+// It is used to explain overflow errors for constant expressions which are
+// only converted to their (final) type after the entire expression has been
+// evaluated, such as 1<<7 + 2 assigned to an int8 variable.
+func (check *Checker) overflowDetail(x *operand, target Type) string {
+	typ := asBasic(target)
+	if x.mode != constant_ || !isUntyped(x.typ) || typ == nil || !isNumeric(typ) {
+		return ""
+	}
+
+	var innermost func(e ast.Expr) (ast.Expr, constant.Value)
+	innermost = func(e ast.Expr) (ast.Expr, constant.Value) {
+		e = unparen(e)
+		var operands []ast.Expr
+		switch e := e.(type) {
+		case *ast.BinaryExpr:
+			if isComparison(e.Op) {
+				return nil, nil
+			}
+			operands = []ast.Expr{e.X}
+			if !isShift(e.Op) {
+				operands = append(operands, e.Y) // the shift count is not converted
+			}
+		case *ast.UnaryExpr:
+			operands = []ast.Expr{e.X}
+		default:
+			return nil, nil
+		}
+		for _, y := range operands {
+			if y, val := innermost(y); y != nil {
+				return y, val
+			}
+		}
+		if info, found := check.untyped[e]; found && info.mode == constant_ && info.val != nil &&
+			info.val.Kind() != constant.Unknown && !representableConst(info.val, check, typ, nil) {
+			return e, info.val
+		}
+		return nil, nil
+	}
+
+	y, val := innermost(x.expr)
+	if y == nil || y == unparen(x.expr) {
+		return ""
+	}
+	name := opName(y)
+	if name == "" {
+		name = "operation"
+	}
+	return check.sprintf("%s %s = %s overflows first", name, y, val)
+}
+
+// overflowSuffix returns the configured suffix for numeric overflow and
+// truncation error messages, including a leading blank, or the empty
+// string.
+func (check *Checker) overflowSuffix(code errorCode) string {
+	if suffix := check.conf.OverflowMessageSuffix; suffix != "" && (code == _TruncatedFloat || code == _NumericOverflow) {
+		return " " + suffix
+	}
+	return ""
 }
 
 // updateExprType updates the type of x to typ and invokes itself
@@ -533,17 +788,27 @@ func (check *Checker) updateExprType(x ast.Expr, typ Type, final bool) {
 		unreachable()
 	}
 
+	if f := check.conf.TraceTypeUpdate; f != nil {
+		f(x, old.typ, typ, final)
+	}
+
 	// If the new type is not final and still untyped, just
 	// update the recorded type.
 	if !final && isUntyped(typ) {
 		old.typ = asBasic(typ)
 		check.untyped[x] = old
+		if m := check.Untyped; m != nil {
+			m[x] = old.typ
+		}
 		return
 	}
 
 	// Otherwise we have the final (typed or untyped type).
 	// Remove it from the map of yet untyped expressions.
 	delete(check.untyped, x)
+	if m := check.Untyped; m != nil && isUntyped(typ) {
+		m[x] = asBasic(typ)
+	}
 
 	if old.isLhs {
 		// If x is the lhs of a shift, its final type must be integer.
@@ -559,7 +824,7 @@ func (check *Checker) updateExprType(x ast.Expr, typ Type, final bool) {
 	}
 	if old.val != nil {
 		// If x is a constant, it must be representable as a value of typ.
-		c := operand{old.mode, x, old.typ, old.val, 0}
+		c := operand{old.mode, x, old.typ, old.val, 0, false}
 		check.convertUntyped(&c, typ)
 		if c.mode == invalid {
 			return
@@ -580,6 +845,9 @@ func (check *Checker) updateExprVal(x ast.Expr, val constant.Value) {
 
 // convertUntyped attempts to set the type of an untyped value to the target type.
 func (check *Checker) convertUntyped(x *operand, target Type) {
+	if x.mode == invalid || !isUntyped(x.typ) {
+		return // nothing to do; typed operands are not implicitly converted
+	}
 	newType, val, code := check.implicitTypeAndValue(x, target)
 	if code != 0 {
 		check.invalidConversion(code, x, target.Underlying())
@@ -587,9 +855,13 @@ func (check *Checker) convertUntyped(x *operand, target Type) {
 		return
 	}
 	if val != nil {
+		check.recordLossyConversion(x.expr, x.val, val)
 		x.val = val
 		check.updateExprVal(x.expr, val)
 	}
+	if asBasic(target) == nil {
+		check.recordImplicitConversion(x.expr, newType)
+	}
 	if newType != x.typ {
 		x.typ = newType
 		check.updateExprType(x.expr, newType, false)
@@ -603,8 +875,13 @@ func (check *Checker) convertUntyped(x *operand, target Type) {
 // If x is a constant operand, the returned constant.Value will be the
 // representation of x in this context.
 func (check *Checker) implicitTypeAndValue(x *operand, target Type) (Type, constant.Value, errorCode) {
+	// Typed operands don't change; check them first, before expanding target.
+	// isTyped(x.typ) is the same as !isUntyped(x.typ).
+	if isTyped(x.typ) {
+		return x.typ, nil, 0
+	}
 	target = expand(target)
-	if x.mode == invalid || isTyped(x.typ) || target == Typ[Invalid] {
+	if x.mode == invalid || target == Typ[Invalid] {
 		return x.typ, nil, 0
 	}
 
@@ -624,6 +901,11 @@ func (check *Checker) implicitTypeAndValue(x *operand, target Type) (Type, const
 
 	switch t := optype(target).(type) {
 	case *Basic:
+		if check != nil && check.conf.AllowIntAsBool && isBoolean(t) {
+			if v := intAsBool(x); v != nil {
+				return target, v, 0
+			}
+		}
 		if x.mode == constant_ {
 			v, code := check.representation(x, t)
 			if code != 0 {
@@ -699,7 +981,81 @@ func (check *Checker) implicitTypeAndValue(x *operand, target Type) (Type, const
 	return target, nil, 0
 }
 
-func (check *Checker) comparison(x, y *operand, op token.Token) {
+// enterCompositeLit tracks the nesting depth of composite literals for
+// Info.MaxLitDepth. The returned function must be called after lit has
+// been checked; for an outermost literal it records the maximum depth.
+func (check *Checker) enterCompositeLit(lit *ast.CompositeLit) func() {
+	check.litDepth++
+	if check.litDepth > check.maxLitDepth {
+		check.maxLitDepth = check.litDepth
+	}
+	return func() {
+		check.litDepth--
+		if check.litDepth == 0 {
+			check.recordMaxLitDepth(lit, check.maxLitDepth)
+			check.maxLitDepth = 0
+		}
+	}
+}
+
+// allowUnexportedFieldLit reports whether Config.AllowUnexportedFieldLit
+// permits the implicit assignment to the unexported field fld (of another
+// package) in a struct literal of type litType.
+func (check *Checker) allowUnexportedFieldLit(fld *Var, litType Type) bool {
+	f := check.conf.AllowUnexportedFieldLit
+	return f != nil && f(fld, litType)
+}
+
+// isBasicLit reports whether the (possibly parenthesized) expression e
+// is a basic literal.
+func isBasicLit(e ast.Expr) bool {
+	_, ok := unparen(e).(*ast.BasicLit)
+	return ok
+}
+
+// isEllipsisArray reports whether the (possibly parenthesized) type
+// expression e is an array type of the form [...]T.
+func isEllipsisArray(e ast.Expr) bool {
+	if a, _ := unparen(e).(*ast.ArrayType); a != nil {
+		_, ok := a.Len.(*ast.Ellipsis)
+		return ok
+	}
+	return false
+}
+
+// isZeroConst reports whether the constant value x is the zero value of
+// its type: false, 0, or "".
+func isZeroConst(x constant.Value) bool {
+	switch x.Kind() {
+	case constant.Bool:
+		return !constant.BoolVal(x)
+	case constant.String:
+		return constant.StringVal(x) == ""
+	case constant.Int, constant.Float, constant.Complex:
+		return constant.Sign(x) == 0
+	}
+	return false
+}
+
+// intAsBool returns the boolean value denoted by x if x is the
+// untyped integer constant 0 or 1 (see Config.AllowIntAsBool);
+// otherwise the result is nil.
+func intAsBool(x *operand) constant.Value {
+	if x.mode != constant_ || !isUntyped(x.typ) || !isInteger(x.typ) || x.val.Kind() != constant.Int {
+		return nil
+	}
+	switch {
+	case constant.Sign(x.val) == 0:
+		return constant.MakeBool(false)
+	case constant.Compare(x.val, token.EQL, constant.MakeInt64(1)):
+		return constant.MakeBool(true)
+	}
+	return nil
+}
+
+// If e != nil, it must be the comparison expression; it is nil for the
+// implicit comparisons of switch case values.
+func (check *Checker) comparison(x, y *operand, op token.Token, e *ast.BinaryExpr) {
 	// spec: "In any comparison, the first operand must be assignable
 	// to the type of the second operand, or vice versa."
 	err := ""
@@ -724,6 +1080,9 @@ func (check *Checker) comparison(x, y *operand, op token.Token) {
 				typ = y.typ
 			}
 			err = check.sprintf("operator %s not defined for %s", op, typ)
+			if (op == token.EQL || op == token.NEQ) && isStructOrArray(typ) && !comparable(typ, nil) {
+				err += ": " + incomparableReason(typ, check.qualifier, nil)
+			}
 			code = _UndefinedOp
 		}
 	} else {
@@ -732,11 +1091,33 @@ func (check *Checker) comparison(x, y *operand, op token.Token) {
 	}
 
 	if err != "" {
-		check.errorf(x, code, "cannot compare %s %s %s (%s)", x.expr, op, y.expr, err)
+		check.typedErrorf(x, code, []Type{x.typ, y.typ}, "cannot compare %s %s %s (%s)", x.expr, op, y.expr, err)
 		x.mode = invalid
 		return
 	}
 
+	if e != nil {
+		side := 0
+		if x.mode == constant_ {
+			side |= 1
+		}
+		if y.mode == constant_ {
+			side |= 2
+		}
+		check.recordComparisonConstantSide(e, side)
+		if check.conf.Suggestions && side == 3 && isBasicLit(x.expr) && isBasicLit(y.expr) && constant.Compare(x.val, token.EQL, y.val) {
+			check.recordTautologicalComparison(e)
+		}
+		if op == token.EQL || op == token.NEQ {
+			check.recordComparisonKind(e, "equality")
+			if check.AlwaysFalsePointerCompare != nil && check.isFreshPointer(x) && check.isFreshPointer(y) {
+				check.recordAlwaysFalsePointerCompare(e)
+			}
+		} else {
+			check.recordComparisonKind(e, "ordering")
+		}
+	}
+
 	if x.mode == constant_ && y.mode == constant_ {
 		x.val = constant.MakeBool(constant.Compare(x.val, op, y.val))
 		// The operands are never materialized; no need to update
@@ -756,6 +1137,87 @@ func (check *Checker) comparison(x, y *operand, op token.Token) {
 	x.typ = Typ[UntypedBool]
 }
 
+// isFreshPointer reports whether x is the address of a composite literal
+// of non-zero size, which always denotes a newly allocated variable.
+func (check *Checker) isFreshPointer(x *operand) bool {
+	u, _ := unparen(x.expr).(*ast.UnaryExpr)
+	if u == nil || u.Op != token.AND {
+		return false
+	}
+	if _, ok := unparen(u.X).(*ast.CompositeLit); !ok {
+		return false
+	}
+	p := asPointer(x.typ)
+	return p != nil && check.conf.sizeof(p.base) > 0
+}
+
+// chainedComparison reports whether x op y is a chained comparison such as
+// a < b < c, where x is the (unparenthesized) untyped boolean result of a
+// comparison and y is not a boolean. If so, an error is reported.
+// Such expressions are parsed as (a < b) < c, which would otherwise lead
+// to a confusing conversion error for the boolean result of a < b.
+func (check *Checker) chainedComparison(x, y *operand, op token.Token) bool {
+	b, _ := x.expr.(*ast.BinaryExpr)
+	if b == nil || !isComparison(b.Op) || x.typ != Typ[UntypedBool] || isBoolean(y.typ) {
+		return false
+	}
+	check.invalidOp(x, _MismatchedTypes, "chained comparison: did you mean (%s %s %s) && (%s %s %s)?", b.X, b.Op, b.Y, b.Y, op, y.expr)
+	return true
+}
+
+// nilComparison reports whether one of x and y is the predeclared nil and
+// the other is a typed operand whose type has no nil value. If so, an error
+// is reported. Such comparisons would otherwise fail with a less specific
+// error when converting nil to the other operand's type.
+func (check *Checker) nilComparison(x, y *operand) bool {
+	if y.isNil() {
+		x, y = y, x
+	}
+	if !x.isNil() || y.isNil() || isUntyped(y.typ) || hasNil(y.typ) || y.typ == Typ[Invalid] {
+		return false
+	}
+	check.errorf(y, _InvalidNilCompare, "cannot compare %s to nil: %s is never nil", y.expr, y.typ)
+	return true
+}
+
+// boolArithmetic reports whether op is an arithmetic operator and one of
+// the operands is a boolean. If so, an error is reported. For binary
+// operations, this avoids a confusing conversion error if the other
+// operand is an untyped numeric constant, as in true + 1.
+func (check *Checker) boolArithmetic(e ast.Expr, op token.Token, operands ...*operand) bool {
+	switch op {
+	case token.ADD, token.SUB, token.MUL, token.QUO, token.REM:
+		// arithmetic operator
+	default:
+		return false
+	}
+	_, unary := e.(*ast.UnaryExpr)
+	// Suggestions for boolean operands (such as || for +) only make
+	// sense if all operands are booleans.
+	allBool := true
+	for _, x := range operands {
+		if !isBoolean(x.typ) {
+			allBool = false
+		}
+	}
+	for _, x := range operands {
+		if isBoolean(x.typ) {
+			msg := "booleans cannot be used in arithmetic"
+			if allBool {
+				if hint := check.opSuggestion(op, x.typ, unary); hint != "" {
+					msg += "; " + hint
+				}
+			}
+			check.invalidOp(x, _UndefinedOp, "operator %s not defined for %s (%s)", op, x, msg)
+			if e != nil {
+				check.recordBoolInArithmetic(e)
+			}
+			return true
+		}
+	}
+	return false
+}
+
 // If e != nil, it must be the shift expression; it may be nil for non-constant shifts.
 func (check *Checker) shift(x, y *operand, e ast.Expr, op token.Token) {
 	// TODO(gri) This function seems overly complex. Revisit.
@@ -775,6 +1237,13 @@ func (check *Checker) shift(x, y *operand, e ast.Expr, op token.Token) {
 		return
 	}
 
+	if check.conf.StrictShiftOperands && x.mode == constant_ && isUntyped(x.typ) && !isInteger(x.typ) {
+		// x is an untyped floating-point or complex constant representable as an integer
+		check.invalidOp(x, _InvalidShiftOperand, "shifted operand %s must be an integer constant (float literal not allowed)", x)
+		x.mode = invalid
+		return
+	}
+
 	// spec: "The right operand in a shift expression must have integer type
 	// or be an untyped constant representable by a value of type uint."
 
@@ -818,10 +1287,13 @@ func (check *Checker) shift(x, y *operand, e ast.Expr, op token.Token) {
 				return
 			}
 			// rhs must be within reasonable bounds in constant shifts
-			const shiftBound = 1023 - 1 + 52 // so we can express smallestFloat64 (see issue #44057)
+			bound := shiftBound
+			if n := check.conf.MaxShiftCount; n > 0 {
+				bound = uint64(n)
+			}
 			s, ok := constant.Uint64Val(y.val)
-			if !ok || s > shiftBound {
-				check.invalidOp(y, _InvalidShiftCount, "invalid shift count %s", y)
+			if !ok || s > bound {
+				check.invalidOp(y, _InvalidShiftCount, "invalid shift count %s: must be <= %d", y, bound)
 				x.mode = invalid
 				return
 			}
@@ -840,6 +1312,15 @@ func (check *Checker) shift(x, y *operand, e ast.Expr, op token.Token) {
 				opPos = b.OpPos
 			}
 			check.overflow(x, op, opPos)
+			if x.mode == constant_ {
+				check.chargeConstFold(x, opPos)
+				if e != nil {
+					check.recordConstantOrigin(e, "folded-shift")
+				}
+				if check.ShiftToZero != nil && check.shiftExceedsWidth(x.typ, y) && constant.Sign(x.val) == 0 {
+					check.recordShiftToZero(e)
+				}
+			}
 			return
 		}
 
@@ -870,6 +1351,9 @@ func (check *Checker) shift(x, y *operand, e ast.Expr, op token.Token) {
 			}
 			// keep x's type
 			x.mode = value
+			if e != nil {
+				check.recordPendingShiftResult(e)
+			}
 			return
 		}
 	}
@@ -881,9 +1365,28 @@ func (check *Checker) shift(x, y *operand, e ast.Expr, op token.Token) {
 		return
 	}
 
+	// A right shift of a signed value yields -1 for negative values.
+	if check.ShiftToZero != nil && (op == token.SHL || isUnsigned(x.typ)) && check.shiftExceedsWidth(x.typ, y) {
+		check.recordShiftToZero(e)
+	}
+
 	x.mode = value
 }
 
+// shiftExceedsWidth reports whether y is a constant shift count that is
+// at least the size in bits of the typed integer type typ.
+func (check *Checker) shiftExceedsWidth(typ Type, y *operand) bool {
+	if y.mode != constant_ || !isTyped(typ) || !isInteger(typ) {
+		return false
+	}
+	yval := constant.ToInt(y.val)
+	if yval.Kind() != constant.Int {
+		return false
+	}
+	s, ok := constant.Uint64Val(yval)
+	return !ok || s >= uint64(check.conf.sizeof(typ))*8
+}
+
 var binaryOpPredicates opPredicates
 
 func init() {
@@ -921,24 +1424,52 @@ func (check *Checker) binary(x *operand, e ast.Expr, lhs, rhs ast.Expr, op token
 		x.expr = y.expr
 		return
 	}
+	if e != nil {
+		check.recordOpPosition(e, opPos)
+	}
+
+	check.binaryOperands(x, &y, e, op, opPos)
+}
 
+// binaryOperands is like binary but for the evaluated (valid) operands x and y.
+func (check *Checker) binaryOperands(x, y *operand, e ast.Expr, op token.Token, opPos token.Pos) {
 	if isShift(op) {
-		check.shift(x, &y, e, op)
+		check.shift(x, y, e, op)
+		return
+	}
+
+	if isComparison(op) && (check.chainedComparison(x, y, op) || check.nilComparison(x, y)) {
+		x.mode = invalid
 		return
 	}
 
+	if check.boolArithmetic(e, op, x, y) {
+		x.mode = invalid
+		return
+	}
+
+	var xk, yk BasicKind // untyped kinds of x and y before conversion, if any
+	if check.ArithmeticWidening != nil && isUntyped(x.typ) && isUntyped(y.typ) {
+		xk, yk = asBasic(x.typ).kind, asBasic(y.typ).kind
+	}
+
 	check.convertUntyped(x, y.typ)
 	if x.mode == invalid {
 		return
 	}
-	check.convertUntyped(&y, x.typ)
+	check.convertUntyped(y, x.typ)
 	if y.mode == invalid {
 		x.mode = invalid
 		return
 	}
 
+	if xk != yk {
+		check.recordArithmeticWidening(e, xk, yk, x, y)
+	}
+
 	if isComparison(op) {
-		check.comparison(x, &y, op)
+		b, _ := e.(*ast.BinaryExpr)
+		check.comparison(x, y, op, b)
 		return
 	}
 
@@ -950,13 +1481,13 @@ func (check *Checker) binary(x *operand, e ast.Expr, lhs, rhs ast.Expr, op token
 			if e != nil {
 				posn = e
 			}
-			check.invalidOp(posn, _MismatchedTypes, "mismatched types %s and %s", x.typ, y.typ)
+			check.typedErrorf(posn, _MismatchedTypes, []Type{x.typ, y.typ}, "invalid operation: mismatched types %s and %s%s", x.typ, y.typ, conversionHint(x, y))
 		}
 		x.mode = invalid
 		return
 	}
 
-	if !check.op(binaryOpPredicates, x, op) {
+	if !check.op(binaryOpPredicates, x, op, false) {
 		x.mode = invalid
 		return
 	}
@@ -964,7 +1495,8 @@ func (check *Checker) binary(x *operand, e ast.Expr, lhs, rhs ast.Expr, op token
 	if op == token.QUO || op == token.REM {
 		// check for zero divisor
 		if (x.mode == constant_ || isInteger(x.typ)) && y.mode == constant_ && constant.Sign(y.val) == 0 {
-			check.invalidOp(&y, _DivByZero, "division by zero")
+			check.invalidOp(y, _DivByZero, "division by zero")
+			check.recordDivByZero(e, y)
 			x.mode = invalid
 			return
 		}
@@ -974,7 +1506,14 @@ func (check *Checker) binary(x *operand, e ast.Expr, lhs, rhs ast.Expr, op token
 			re, im := constant.Real(y.val), constant.Imag(y.val)
 			re2, im2 := constant.BinaryOp(re, token.MUL, re), constant.BinaryOp(im, token.MUL, im)
 			if constant.Sign(re2) == 0 && constant.Sign(im2) == 0 {
-				check.invalidOp(&y, _DivByZero, "division by zero")
+				// The divisor is not zero but too small. If it is
+				// computed by an operation, point at the operator.
+				var posn positioner = y
+				if b, _ := unparen(y.expr).(*ast.BinaryExpr); b != nil {
+					posn = atPos(b.OpPos)
+				}
+				check.invalidOp(posn, _DivByZero, "division by zero (complex divisor underflows to zero)")
+				check.recordDivByZero(e, y)
 				x.mode = invalid
 				return
 			}
@@ -982,6 +1521,17 @@ func (check *Checker) binary(x *operand, e ast.Expr, lhs, rhs ast.Expr, op token
 	}
 
 	if x.mode == constant_ && y.mode == constant_ {
+		if op == token.ADD && isString(x.typ) && check.conf.NoStringConstFold {
+			// String concatenation is done at run time. Untyped operands
+			// have their default type string, as the result is a value.
+			if isUntyped(x.typ) {
+				check.updateExprType(x.expr, Typ[String], true)
+				check.updateExprType(y.expr, Typ[String], true)
+				x.typ = Typ[String]
+			}
+			x.mode = value
+			return
+		}
 		// if either x or y has an unknown value, the result is unknown
 		if x.val.Kind() == constant.Unknown || y.val.Kind() == constant.Unknown {
 			x.val = constant.MakeUnknown()
@@ -992,9 +1542,26 @@ func (check *Checker) binary(x *operand, e ast.Expr, lhs, rhs ast.Expr, op token
 		if op == token.QUO && isInteger(x.typ) {
 			op = token.QUO_ASSIGN
 		}
+		if op == token.QUO_ASSIGN && isTyped(x.typ) && constant.Compare(y.val, token.EQL, constant.MakeInt64(-1)) {
+			// The only typed integer division that overflows is the
+			// division of the minimum value by -1. Check -x rather than
+			// x / -1: go/constant computes the latter with int64 arithmetic
+			// which wraps around for the minimum int64 value.
+			if typ := asBasic(x.typ); typ != nil && !representableConst(constant.UnaryOp(token.SUB, x.val, 0), check, typ, nil) {
+				check.errorf(atPos(opPos), _NumericOverflow, "constant %s overflow: %s / %s", opName(e), x.val, y.val)
+				x.mode = invalid
+				return
+			}
+		}
 		x.val = constant.BinaryOp(x.val, op, y.val)
 		x.expr = e
 		check.overflow(x, op, opPos)
+		if x.mode == constant_ {
+			check.chargeConstFold(x, opPos)
+			if e != nil {
+				check.recordConstantOrigin(e, "folded-binary")
+			}
+		}
 		return
 	}
 
@@ -1002,6 +1569,80 @@ func (check *Checker) binary(x *operand, e ast.Expr, lhs, rhs ast.Expr, op token
 	// x.typ is unchanged
 }
 
+// conversionHint returns a suggestion to convert x to the type of y, or y to
+// the type of x, for use in a mismatched types error. A conversion is only
+// suggested if x and y are of (different) numeric types and exactly one of
+// the two conversions is widening. Otherwise the result is "".
+func conversionHint(x, y *operand) string {
+	xb, _ := under(x.typ).(*Basic)
+	yb, _ := under(y.typ).(*Basic)
+	if xb == nil || yb == nil || !isNumeric(xb) || !isNumeric(yb) {
+		return ""
+	}
+	xw := wideningConversion(xb, yb)
+	yw := wideningConversion(yb, xb)
+	switch {
+	case xw && !yw:
+		return fmt.Sprintf(" (consider converting %s to %s)", ExprString(x.expr), y.typ)
+	case yw && !xw:
+		return fmt.Sprintf(" (consider converting %s to %s)", ExprString(y.expr), x.typ)
+	}
+	return ""
+}
+
+// wideningConversion reports whether each value of the typed numeric type
+// from is in the range of the typed numeric type to, independent of the
+// sizes of int and uint (which may be 32 or 64 bits). Integer to floating-point
+// conversions are considered widening even if they may lose precision;
+// conversions from floating-point to integer types never are. Conversions
+// involving uintptr are not considered widening.
+func wideningConversion(from, to *Basic) bool {
+	// minimum and maximum size in bits, for complex types the size of each part
+	bits := func(t *Basic) (min, max int) {
+		switch t.kind {
+		case Int8, Uint8:
+			return 8, 8
+		case Int16, Uint16:
+			return 16, 16
+		case Int32, Uint32, Float32, Complex64:
+			return 32, 32
+		case Int, Uint:
+			return 32, 64
+		case Int64, Uint64, Float64, Complex128:
+			return 64, 64
+		}
+		return 0, 0 // uintptr, untyped types
+	}
+	_, fmax := bits(from)
+	tmin, _ := bits(to)
+	if fmax == 0 || tmin == 0 {
+		return false
+	}
+	switch {
+	case isInteger(from) && isInteger(to):
+		switch {
+		case isUnsigned(from) == isUnsigned(to):
+			return fmax <= tmin
+		case isUnsigned(from):
+			return fmax < tmin
+		}
+		return false // signed to unsigned
+	case isInteger(from):
+		return true // integer to floating-point or complex
+	case isFloat(from) || isComplex(from) && isComplex(to):
+		return !isInteger(to) && fmax <= tmin
+	}
+	return false
+}
+
+// shiftBound is the default maximum shift count in constant shifts (see
+// Config.MaxShiftCount). The smallest positive float64 value is
+// 1 / 2**(1023 - 1 + 52): 1023 - 1 is the magnitude of the minimum
+// (normalized) exponent and 52 the number of explicit mantissa bits of
+// denormalized numbers. The bound permits expressing this value as
+// 1.0 / (1 << shiftBound) (see issue #44057).
+var shiftBound uint64 = 1023 - 1 + 52
+
 // exprKind describes the kind of an expression; the kind
 // determines if an expression is valid in 'statement context'.
 type exprKind int
@@ -1026,9 +1667,20 @@ func (check *Checker) rawExpr(x *operand, e ast.Expr, hint Type) exprKind {
 		}()
 	}
 
+	// track calls and receives for Info.HasEffects
+	outer := check.hasCallOrRecv
+	if check.HasEffects != nil {
+		check.hasCallOrRecv = false
+	}
+
 	kind := check.exprInternal(x, e, hint)
 	check.record(x)
 
+	if check.HasEffects != nil {
+		check.recordHasEffects(e, check.hasCallOrRecv)
+		check.hasCallOrRecv = check.hasCallOrRecv || outer
+	}
+
 	return kind
 }
 
@@ -1058,30 +1710,12 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 		switch e.Kind {
 		case token.INT, token.FLOAT, token.IMAG:
 			check.langCompat(e)
-			// The max. mantissa precision for untyped numeric values
-			// is 512 bits, or 4048 bits for each of the two integer
-			// parts of a fraction for floating-point numbers that are
-			// represented accurately in the go/constant package.
-			// Constant literals that are longer than this many bits
-			// are not meaningful; and excessively long constants may
-			// consume a lot of space and time for a useless conversion.
-			// Cap constant length with a generous upper limit that also
-			// allows for separators between all digits.
-			const limit = 10000
-			if len(e.Value) > limit {
-				check.errorf(e, _InvalidConstVal, "excessively long constant: %s... (%d chars)", e.Value[:10], len(e.Value))
-				goto Error
-			}
 		}
-		x.setConst(e.Kind, e.Value)
-		if x.mode == invalid {
-			// The parser already establishes syntactic correctness.
-			// If we reach here it's because of number under-/overflow.
-			// TODO(gri) setConst (and in turn the go/constant package)
-			// should return an error describing the issue.
-			check.errorf(e, _InvalidConstVal, "malformed constant: %s", e.Value)
+		if msg := x.setBasicLit(e.Kind, e.Value); msg != "" {
+			check.error(e, _InvalidConstVal, msg)
 			goto Error
 		}
+		check.recordConstantOrigin(e, "literal")
 
 	case *ast.FuncLit:
 		if sig, ok := check.typ(e.Type).(*Signature); ok {
@@ -1107,6 +1741,10 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 		}
 
 	case *ast.CompositeLit:
+		check.recordLitElemCount(e)
+		if check.MaxLitDepth != nil {
+			defer check.enterCompositeLit(e)()
+		}
 		var typ, base Type
 
 		switch {
@@ -1124,8 +1762,17 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 					break
 				}
 			}
+			check.typeName = nil
 			typ = check.typ(e.Type)
 			base = typ
+			if check.LitTypeAlias != nil {
+				switch e.Type.(type) {
+				case *ast.Ident, *ast.SelectorExpr:
+					if tname := check.typeName; tname != nil && tname.IsAlias() {
+						check.recordLitTypeAlias(e)
+					}
+				}
+			}
 
 		case hint != nil:
 			// no composite literal type present - use hint (element type of enclosing type)
@@ -1135,6 +1782,9 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 		default:
 			// TODO(gri) provide better error messages depending on context
 			check.error(e, _UntypedLit, "missing type in composite literal")
+			if check.conf.RecoverCompositeLitType {
+				check.useLitElts(e.Elts)
+			}
 			goto Error
 		}
 
@@ -1156,7 +1806,13 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 					key, _ := kv.Key.(*ast.Ident)
 					// do all possible checks early (before exiting due to errors)
 					// so we don't drop information on the floor
+					if key != nil {
+						check.litFieldPath = append(check.litFieldPath, key.Name)
+					}
 					check.expr(x, kv.Value)
+					if key != nil {
+						check.litFieldPath = check.litFieldPath[:len(check.litFieldPath)-1]
+					}
 					if key == nil {
 						check.errorf(kv, _InvalidLitField, "invalid field name %s in struct literal", kv.Key)
 						continue
@@ -1169,7 +1825,14 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 					fld := fields[i]
 					check.recordUse(key, fld)
 					etyp := fld.typ
-					check.assignment(x, etyp, "struct literal")
+					outer := check.litFieldPath // field names of enclosing struct literals
+					check.assignmentf(x, etyp, func() string {
+						path := append(outer[:len(outer):len(outer)], key.Name)
+						return "struct literal field " + strings.Join(path, ".")
+					})
+					if check.conf.Suggestions && x.mode == constant_ && asBasic(etyp) != nil && isZeroConst(x.val) {
+						check.recordExplicitZeroField(kv)
+					}
 					// 0 <= i < len(fields)
 					if visited[i] {
 						check.errorf(kv, _DuplicateLitField, "duplicate field name %s in struct literal", key.Name)
@@ -1177,6 +1840,7 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 					}
 					visited[i] = true
 				}
+				check.recordOmittedEmbedded(e, fields, visited)
 			} else {
 				// no element must have a key
 				for i, e := range e.Elts {
@@ -1191,17 +1855,26 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 					}
 					// i < len(fields)
 					fld := fields[i]
-					if !fld.Exported() && fld.pkg != check.pkg {
+					if !fld.Exported() && fld.pkg != check.pkg && !check.allowUnexportedFieldLit(fld, base) {
 						check.errorf(x,
 							_UnexportedLitField,
 							"implicit assignment to unexported field %s in %s literal", fld.name, typ)
 						continue
 					}
+					if !fld.Exported() && check.conf.ForbidPositionalUnexported {
+						check.softErrorf(x,
+							_UnexportedLitField,
+							"implicit assignment to unexported field %s in %s literal (use a keyed literal)", fld.name, typ)
+					}
 					etyp := fld.typ
 					check.assignment(x, etyp, "struct literal")
 				}
 				if len(e.Elts) < len(fields) {
-					check.error(inNode(e, e.Rbrace), _InvalidStructLit, "too few values in struct literal")
+					if check.conf.AllowPartialPositionalStructLit {
+						check.recordPartialStructLit(e, len(fields)-len(e.Elts))
+					} else {
+						check.error(inNode(e, e.Rbrace), _InvalidStructLit, "too few values in struct literal")
+					}
 					// ok to continue
 				}
 			}
@@ -1215,6 +1888,7 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 				goto Error
 			}
 			n := check.indexedElts(e.Elts, utyp.elem, utyp.len)
+			check.recordLitMaxIndex(e, n)
 			// If we have an array of unknown length (usually [...]T arrays, but also
 			// arrays [n]T where n is invalid) set the length now that we know it and
 			// record the type for the array (usually done by check.typ which is not
@@ -1232,6 +1906,9 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 				if e.Type != nil {
 					check.recordTypeAndValue(e.Type, typexpr, utyp, nil)
 				}
+				if !isEllipsisArray(e.Type) {
+					check.recordInferredArrayLen(e, n)
+				}
 			}
 
 		case *Slice:
@@ -1241,7 +1918,8 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 				check.error(e, _InvalidTypeCycle, "illegal cycle in type declaration")
 				goto Error
 			}
-			check.indexedElts(e.Elts, utyp.elem, -1)
+			n := check.indexedElts(e.Elts, utyp.elem, -1)
+			check.recordLitMaxIndex(e, n)
 
 		case *Map:
 			// Prevent crash if the map referred to is not yet set up.
@@ -1250,7 +1928,11 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 				check.error(e, _InvalidTypeCycle, "illegal cycle in type declaration")
 				goto Error
 			}
-			visited := make(map[interface{}][]Type, len(e.Elts))
+			type keyInfo struct {
+				typ Type
+				pos token.Pos // position of the first key with this value and type
+			}
+			visited := make(map[interface{}][]keyInfo, len(e.Elts))
 			for _, e := range e.Elts {
 				kv, _ := e.(*ast.KeyValueExpr)
 				if kv == nil {
@@ -1264,24 +1946,35 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 				}
 				if x.mode == constant_ {
 					duplicate := false
+					var firstPos token.Pos
 					// if the key is of interface type, the type is also significant when checking for duplicates
 					xkey := keyVal(x.val)
 					if asInterface(utyp.key) != nil {
-						for _, vtyp := range visited[xkey] {
-							if check.identical(vtyp, x.typ) {
+						for _, v := range visited[xkey] {
+							if check.identical(v.typ, x.typ) {
 								duplicate = true
+								firstPos = v.pos
 								break
 							}
 						}
-						visited[xkey] = append(visited[xkey], x.typ)
+						if !duplicate {
+							visited[xkey] = append(visited[xkey], keyInfo{x.typ, kv.Key.Pos()})
+						}
+					} else if v := visited[xkey]; v != nil {
+						duplicate = true
+						firstPos = v[0].pos
 					} else {
-						_, duplicate = visited[xkey]
-						visited[xkey] = nil
+						visited[xkey] = []keyInfo{{x.typ, kv.Key.Pos()}}
 					}
 					if duplicate {
 						check.errorf(x, _DuplicateLitKey, "duplicate key %s in map literal", x.val)
+						if f := check.conf.OnDuplicateLitKey; f != nil {
+							f(kv.Key, firstPos, kv.Key.Pos())
+						}
 						continue
 					}
+				} else {
+					check.recordDynamicMapKey(kv.Key)
 				}
 				check.exprWithHint(x, kv.Value, utyp.elem)
 				check.assignment(x, utyp.elem, "map literal")
@@ -1306,6 +1999,10 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 			}
 		}
 
+		if f := check.conf.OnCompositeLit; f != nil {
+			f(e, typ, len(e.Elts))
+		}
+
 		x.mode = value
 		x.typ = typ
 
@@ -1353,6 +2050,11 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 		if T == Typ[Invalid] {
 			goto Error
 		}
+		check.recordAssertTypeExpr(e, T)
+		if t := asInterface(T); t != nil && check.AssertInterfaceMethodCount != nil {
+			check.completeInterface(token.NoPos, t)
+			check.recordAssertInterfaceMethodCount(e, len(t.allMethods))
+		}
 		check.typeAssertion(x, x, xtyp, T)
 		x.mode = commaok
 		x.typ = T
@@ -1371,6 +2073,7 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 			if typ := asPointer(x.typ); typ != nil {
 				x.mode = variable
 				x.typ = typ.base
+				check.recordUnaryOp(e, token.MUL, x.mode)
 			} else {
 				check.invalidOp(x, _InvalidIndirection, "cannot indirect %s", x)
 				goto Error
@@ -1382,6 +2085,7 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 		if x.mode == invalid {
 			goto Error
 		}
+		check.recordUnaryOp(e, e.Op, x.mode)
 		if e.Op == token.ARROW {
 			x.expr = e
 			return statement // receive operations may appear in statement context
@@ -1418,6 +2122,9 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 	}
 
 	// everything went well
+	if _, ok := e.(*ast.IndexExpr); !ok {
+		x.viaPointer = false // set for an operand of e, if at all
+	}
 	x.expr = e
 	return expression
 
@@ -1427,6 +2134,25 @@ Error:
 	return statement // avoid follow-up errors
 }
 
+// ConstEqual reports whether a and b denote the same key in the sense used
+// by the type checker when it looks for duplicate constant keys in map
+// literals (and duplicate constant cases in switch statements).
+//
+// The comparison is based on a normalized Go value for each constant:
+//
+//   - Constants of different kinds are never equal; in particular, the
+//     integer 1 and the floating-point 1.0 are different. The type checker
+//     converts keys to the map key type before comparing them, so typed
+//     and untyped keys of the same type have the same kind.
+//   - Integer constants are equal if they have the same value.
+//   - Floating-point and complex constants are compared after rounding
+//     (each component) to float64; distinct values that round to the same
+//     float64 are equal.
+//
+func ConstEqual(a, b constant.Value) bool {
+	return keyVal(a) == keyVal(b)
+}
+
 func keyVal(x constant.Value) interface{} {
 	switch x.Kind() {
 	case constant.Bool:
@@ -1440,6 +2166,7 @@ func keyVal(x constant.Value) interface{} {
 		if v, ok := constant.Uint64Val(x); ok {
 			return v
 		}
+		return bigIntKey(x.ExactString())
 	case constant.Float:
 		v, _ := constant.Float64Val(x)
 		return v
@@ -1451,6 +2178,10 @@ func keyVal(x constant.Value) interface{} {
 	return x
 }
 
+// A bigIntKey is the key value of an integer constant that
+// doesn't fit into an int64 or uint64.
+type bigIntKey string
+
 // typeAssertion checks that x.(T) is legal; xtyp must be the type of x.
 func (check *Checker) typeAssertion(at positioner, x *operand, xtyp *Interface, T Type) {
 	method, wrongType := check.assertableTo(xtyp, T)
@@ -1492,6 +2223,9 @@ func (check *Checker) multiExpr(x *operand, e ast.Expr) {
 //
 func (check *Checker) exprWithHint(x *operand, e ast.Expr, hint Type) {
 	assert(hint != nil)
+	if f := check.conf.TraceHint; f != nil {
+		f(e, hint)
+	}
 	check.rawExpr(x, e, hint)
 	check.exclude(x, 1<<novalue|1<<builtin|1<<typexpr)
 	check.singleValue(x)
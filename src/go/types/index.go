@@ -10,6 +10,7 @@ import (
 	"go/ast"
 	"go/constant"
 	"go/internal/typeparams"
+	"go/token"
 )
 
 // If e is a valid function instantiation, indexExpr returns true.
@@ -17,6 +18,7 @@ import (
 // it is the caller's responsibility to instantiate the function.
 func (check *Checker) indexExpr(x *operand, e *ast.IndexExpr) (isFuncInst bool) {
 	check.exprOrType(x, e.X)
+	x.viaPointer = false
 
 	switch x.mode {
 	case invalid:
@@ -37,6 +39,12 @@ func (check *Checker) indexExpr(x *operand, e *ast.IndexExpr) (isFuncInst bool)
 			// function instantiation
 			return true
 		}
+		if x.isNil() {
+			check.invalidOp(x, _NonIndexableOperand, "cannot index nil")
+			check.use(typeparams.UnpackExpr(e.Index)...)
+			x.mode = invalid
+			return false
+		}
 	}
 
 	valid := false
@@ -53,6 +61,7 @@ func (check *Checker) indexExpr(x *operand, e *ast.IndexExpr) (isFuncInst bool)
 			// index are constant
 			x.mode = value
 			x.typ = universeByte // use 'byte' name
+			check.recordStringByteIndex(e)
 		}
 
 	case *Array:
@@ -69,6 +78,7 @@ func (check *Checker) indexExpr(x *operand, e *ast.IndexExpr) (isFuncInst bool)
 			length = typ.len
 			x.mode = variable
 			x.typ = typ.elem
+			x.viaPointer = true
 		}
 
 	case *Slice:
@@ -193,7 +203,10 @@ func (check *Checker) indexExpr(x *operand, e *ast.IndexExpr) (isFuncInst bool)
 		x.typ = Typ[Invalid]
 	}
 
-	check.index(index, length)
+	if _, v := check.index(index, length); length >= 0 && v >= 0 {
+		// 0 <= v < length
+		check.recordStaticInBoundsIndex(e)
+	}
 	return false
 }
 
@@ -204,7 +217,15 @@ func (check *Checker) sliceExpr(x *operand, e *ast.SliceExpr) {
 		return
 	}
 
+	if x.isNil() {
+		check.invalidOp(x, _NonSliceableOperand, "cannot slice nil")
+		check.use(e.Low, e.High, e.Max)
+		x.mode = invalid
+		return
+	}
+
 	valid := false
+	dynamic := false    // set if x is a slice
 	length := int64(-1) // valid if >= 0
 	switch typ := optype(x.typ).(type) {
 	case *Basic:
@@ -229,7 +250,7 @@ func (check *Checker) sliceExpr(x *operand, e *ast.SliceExpr) {
 		valid = true
 		length = typ.len
 		if x.mode != variable {
-			check.invalidOp(x, _NonSliceableOperand, "cannot slice %s (value not addressable)", x)
+			check.invalidOp(x, _NonSliceableOperand, "cannot slice %s (value not addressable; take its address or assign to a variable first)", x)
 			x.mode = invalid
 			return
 		}
@@ -244,6 +265,7 @@ func (check *Checker) sliceExpr(x *operand, e *ast.SliceExpr) {
 
 	case *Slice:
 		valid = true
+		dynamic = true
 		// x.typ doesn't change
 
 	case *_Sum, *_TypeParam:
@@ -293,19 +315,109 @@ func (check *Checker) sliceExpr(x *operand, e *ast.SliceExpr) {
 		ind[i] = x
 	}
 
+	if dynamic && check.SliceIndexAssumptions != nil {
+		var indices []int64
+		for i, expr := range []ast.Expr{e.Low, e.High, e.Max} {
+			if expr != nil && ind[i] >= 0 {
+				indices = append(indices, ind[i])
+			}
+		}
+		if indices != nil {
+			check.recordSliceIndexAssumptions(e, indices)
+		}
+	}
+
 	// constant indices must be in range
 	// (check.index already checks that existing indices >= 0)
+	swapped := false
 L:
 	for i, x := range ind[:len(ind)-1] {
 		if x > 0 {
 			for _, y := range ind[i+1:] {
 				if y >= 0 && x > y {
 					check.errorf(inNode(e, e.Rbrack), _SwappedSliceIndices, "swapped slice indices: %d > %d", x, y)
-					break L // only report one error, ok to continue
+					swapped = true
+					if !check.conf.ReportAllSliceIndexErrors {
+						break L // only report one error, ok to continue
+					}
+				}
+			}
+		}
+	}
+
+	// Non-constant indices of the form v+c (with the same variable v)
+	// are obviously swapped if the offsets are out of order, as in s[i+1:i].
+	// Such slice expressions are valid; they are only reported if requested.
+	if !swapped && check.conf.ReportSwappedOffsetIndices {
+		exprs := [3]ast.Expr{e.Low, e.High, e.Max}
+	L2:
+		for i := range exprs[:2] {
+			if ind[i] >= 0 {
+				continue
+			}
+			xname, xoff, ok := offsetExpr(exprs[i])
+			if !ok {
+				continue
+			}
+			for j := i + 1; j < 3; j++ {
+				if ind[j] >= 0 {
+					continue
+				}
+				if yname, yoff, ok := offsetExpr(exprs[j]); ok && yname == xname && xoff > yoff {
+					check.softErrorf(inNode(e, e.Rbrack), _SwappedSliceIndices, "swapped slice indices: %s > %s", exprs[i], exprs[j])
+					swapped = true
+					break L2
 				}
 			}
 		}
 	}
+
+	// If the length is known, all indices are known (explicitly or by
+	// default) unless they are not constant or out of bounds.
+	if length >= 0 && !swapped && ind[0] >= 0 && ind[1] >= 0 && ind[2] >= 0 {
+		check.recordStaticValidSlice(e)
+	}
+}
+
+// offsetExpr reports whether e is of the form v, v+c, c+v, or v-c, where v
+// is an identifier and c is an integer literal. If so, it returns the name
+// of v and the offset c (negated for v-c).
+func offsetExpr(e ast.Expr) (name string, offset int64, ok bool) {
+	intLit := func(e ast.Expr) (int64, bool) {
+		lit, _ := unparen(e).(*ast.BasicLit)
+		if lit == nil || lit.Kind != token.INT {
+			return 0, false
+		}
+		return constant.Int64Val(constant.MakeFromLiteral(lit.Value, lit.Kind, 0))
+	}
+
+	switch e := unparen(e).(type) {
+	case *ast.Ident:
+		return e.Name, 0, true
+	case *ast.BinaryExpr:
+		x, _ := unparen(e.X).(*ast.Ident)
+		y, _ := unparen(e.Y).(*ast.Ident)
+		switch e.Op {
+		case token.ADD:
+			if x != nil {
+				if c, ok := intLit(e.Y); ok {
+					return x.Name, c, true
+				}
+			}
+			if y != nil {
+				if c, ok := intLit(e.X); ok {
+					return y.Name, c, true
+				}
+			}
+		case token.SUB:
+			if x != nil {
+				if c, ok := intLit(e.Y); ok {
+					return x.Name, -c, true
+				}
+			}
+		}
+	}
+	return "", 0, false
 }
 
 // singleIndex returns the (single) index from the index expression e.
@@ -403,7 +515,7 @@ func (check *Checker) isValidIndex(x *operand, code errorCode, what string, allo
 // literal (maximum index value + 1).
 //
 func (check *Checker) indexedElts(elts []ast.Expr, typ Type, length int64) int64 {
-	visited := make(map[int64]bool, len(elts))
+	visited := make(map[int64]token.Pos, len(elts)) // element positions by index
 	var index, max int64
 	for _, e := range elts {
 		// determine and check index
@@ -427,10 +539,18 @@ func (check *Checker) indexedElts(elts []ast.Expr, typ Type, length int64) int64
 
 		// if we have a valid index, check for duplicate entries
 		if validIndex {
-			if visited[index] {
+			if firstPos, ok := visited[index]; ok {
 				check.errorf(e, _DuplicateLitKey, "duplicate index %d in array or slice literal", index)
+				if f := check.conf.OnDuplicateLitKey; f != nil {
+					key := e
+					if kv, _ := e.(*ast.KeyValueExpr); kv != nil {
+						key = kv.Key
+					}
+					f(key, firstPos, e.Pos())
+				}
+			} else {
+				visited[index] = e.Pos()
 			}
-			visited[index] = true
 		}
 		index++
 		if index > max {
@@ -440,7 +560,20 @@ func (check *Checker) indexedElts(elts []ast.Expr, typ Type, length int64) int64
 		// check element against composite literal element type
 		var x operand
 		check.exprWithHint(&x, eval, typ)
-		check.assignment(&x, typ, "array or slice literal")
+		context := "array or slice literal"
+		if validIndex {
+			context = check.sprintf("%s (element %d)", context, index-1)
+		}
+		var orig constant.Value
+		if check.ElemPrecisionLoss != nil && x.mode == constant_ && isUntyped(x.typ) {
+			if t := asBasic(typ); t != nil && t.kind == Float32 {
+				orig = x.val
+			}
+		}
+		check.assignment(&x, typ, context)
+		if orig != nil && x.mode == constant_ && !constant.Compare(orig, token.EQL, x.val) {
+			check.recordElemPrecisionLoss(eval, orig, x.val)
+		}
 	}
 	return max
 }
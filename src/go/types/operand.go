@@ -52,11 +52,12 @@ var operandModeString = [...]string{
 // The zero value of operand is a ready to use invalid operand.
 //
 type operand struct {
-	mode operandMode
-	expr ast.Expr
-	typ  Type
-	val  constant.Value
-	id   builtinId
+	mode       operandMode
+	expr       ast.Expr
+	typ        Type
+	val        constant.Value
+	id         builtinId
+	viaPointer bool // index expression of a pointer to an array (see TypeAndValue.IndexViaPointer)
 }
 
 // Pos returns the position of the expression corresponding to x.
@@ -215,6 +216,39 @@ func (x *operand) setConst(tok token.Token, lit string) {
 	x.val = val
 }
 
+// setBasicLit sets x to the untyped constant for the basic literal lit
+// of kind tok. If lit doesn't denote a valid constant, x is invalid and
+// the result describes the problem; otherwise the result is "".
+func (x *operand) setBasicLit(tok token.Token, lit string) string {
+	switch tok {
+	case token.INT, token.FLOAT, token.IMAG:
+		// The max. mantissa precision for untyped numeric values
+		// is 512 bits, or 4048 bits for each of the two integer
+		// parts of a fraction for floating-point numbers that are
+		// represented accurately in the go/constant package.
+		// Constant literals that are longer than this many bits
+		// are not meaningful; and excessively long constants may
+		// consume a lot of space and time for a useless conversion.
+		// Cap constant length with a generous upper limit that also
+		// allows for separators between all digits.
+		const limit = 10000
+		if len(lit) > limit {
+			x.mode = invalid
+			x.typ = Typ[Invalid]
+			return fmt.Sprintf("excessively long constant: %s... (%d chars)", lit[:10], len(lit))
+		}
+	}
+	x.setConst(tok, lit)
+	if x.mode == invalid {
+		// The parser already establishes syntactic correctness.
+		// If we reach here it's because of number under-/overflow.
+		// TODO(gri) setConst (and in turn the go/constant package)
+		// should return an error describing the issue.
+		return fmt.Sprintf("malformed constant: %s", lit)
+	}
+	return ""
+}
+
 // isNil reports whether x is the nil value.
 func (x *operand) isNil() bool {
 	return x.mode == value && x.typ == Typ[UntypedNil]
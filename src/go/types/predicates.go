@@ -139,6 +139,105 @@ func comparable(T Type, seen map[Type]bool) bool {
 	return false
 }
 
+// ComparableReason reports whether values of type T are comparable, like
+// Comparable. If they are not, the result also describes why, naming the
+// first component of T that is not comparable, as in "slice field S makes
+// struct non-comparable". Interface types are comparable (even though
+// comparing interface values with non-comparable dynamic types panics at
+// run time).
+func ComparableReason(T Type) (bool, string) {
+	if comparable(T, nil) {
+		return true, ""
+	}
+	return false, incomparableReason(T, nil, nil)
+}
+
+// incomparableReason describes why values of the non-comparable type T
+// are not comparable; qf is used to format types. Struct fields and array
+// elements that are seen again (in invalid, recursive types) are not
+// described again.
+func incomparableReason(T Type, qf Qualifier, seen map[Type]bool) string {
+	if seen == nil {
+		seen = make(map[Type]bool)
+	}
+	seen[T] = true
+
+	// component describes the non-comparable component type typ of a T of
+	// the given kind, including why typ is not comparable if it is composite.
+	component := func(what, kind string, typ Type) string {
+		r := incomparableKind(typ) + " " + what + " makes " + kind + " non-comparable"
+		if isStructOrArray(typ) && !seen[typ] {
+			r += ": " + incomparableReason(typ, qf, seen)
+		}
+		return r
+	}
+
+	switch t := optype(T).(type) {
+	case *Struct:
+		for _, f := range t.fields {
+			if !comparable(f.typ, nil) {
+				return component("field "+f.name, "struct", f.typ)
+			}
+		}
+	case *Array:
+		// Descend into nested arrays and report the innermost
+		// element type directly; it is the cause of the problem.
+		elem := t.elem
+		for {
+			a, _ := optype(elem).(*Array)
+			if a == nil {
+				break
+			}
+			elem = a.elem
+		}
+		r := "array element type " + TypeString(elem, qf) + " is not comparable"
+		if isStructOrArray(elem) && !seen[elem] {
+			r += ": " + incomparableReason(elem, qf, seen)
+		}
+		return r
+	case *_Sum:
+		for _, t := range t.types {
+			if !comparable(t, nil) {
+				return "type list contains non-comparable type " + TypeString(t, qf)
+			}
+		}
+	case *_TypeParam:
+		return "type parameter " + TypeString(T, qf) + " is not comparable"
+	}
+	return incomparableKind(T) + " type"
+}
+
+// incomparableKind returns a short description of the kind of type T,
+// such as "slice", for use in error messages about comparability.
+func incomparableKind(T Type) string {
+	switch t := optype(T).(type) {
+	case *Basic:
+		return t.name
+	case *Slice:
+		return "slice"
+	case *Map:
+		return "map"
+	case *Signature:
+		return "func"
+	case *Struct:
+		return "struct"
+	case *Array:
+		return "array"
+	case *_TypeParam:
+		return "type parameter"
+	}
+	return "non-comparable"
+}
+
+// isStructOrArray reports whether the underlying type of T is a struct or array.
+func isStructOrArray(T Type) bool {
+	switch optype(T).(type) {
+	case *Struct, *Array:
+		return true
+	}
+	return false
+}
+
 // hasNil reports whether a type includes the nil value.
 func hasNil(typ Type) bool {
 	switch t := optype(typ).(type) {
@@ -5,6 +5,8 @@
 package types_test
 
 import (
+	"bytes"
+	"fmt"
 	"go/ast"
 	"go/importer"
 	"go/parser"
@@ -63,6 +65,54 @@ func BenchmarkCheck(b *testing.B) {
 	}
 }
 
+// BenchmarkBinaryExprs measures checking a file with many (mostly typed)
+// binary expressions, whose operands are all passed through convertUntyped.
+func BenchmarkBinaryExprs(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteString("package p\n\n")
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&buf, `func f%d(a, b, c int, x, y float64, s string) (int, float64, bool) {
+	a = a*b + c - (a/b)%%c<<2 + a&b | c
+	x = x*y + y/x - float64(a) + 1.5
+	ok := a < b && x >= y || s+"x" != s && b == 1
+	return a + b*c, x - y, ok
+}
+
+`, i)
+	}
+	runbenchSrc(b, buf.String())
+}
+
+// BenchmarkUntypedSum measures checking a non-constant untyped sum of
+// 10000 terms, which gets its type from the assignment in which it appears.
+func BenchmarkUntypedSum(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteString("package p\n\nfunc _(s uint) {\n\tvar x int64 = 1 << s")
+	for i := 1; i < 10000; i++ {
+		fmt.Fprintf(&buf, " + %d<<s", i)
+	}
+	buf.WriteString("\n\t_ = x\n}\n")
+	runbenchSrc(b, buf.String())
+}
+
+// runbenchSrc benchmarks checking the package consisting of the single file src.
+func runbenchSrc(b *testing.B, src string) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var conf Config
+		info := &Info{Types: make(map[ast.Expr]TypeAndValue)}
+		if _, err := conf.Check("p", fset, []*ast.File{f}, info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func runbench(b *testing.B, path string, ignoreFuncBodies, writeInfo bool) {
 	fset := token.NewFileSet()
 	files, err := pkgFiles(fset, path, 0)
@@ -249,13 +249,18 @@ func (conf *Config) offsetof(typ Type, index []int) int64 {
 }
 
 func (conf *Config) sizeof(T Type) int64 {
+	var z int64
 	if s := conf.Sizes; s != nil {
-		if z := s.Sizeof(T); z >= 0 {
-			return z
+		if z = s.Sizeof(T); z < 0 {
+			panic("Config.Sizes.Sizeof returned a size < 0")
 		}
-		panic("Config.Sizes.Sizeof returned a size < 0")
+	} else {
+		z = stdSizes.Sizeof(T)
 	}
-	return stdSizes.Sizeof(T)
+	if f := conf.OnSizeof; f != nil {
+		f(T, z)
+	}
+	return z
 }
 
 // align returns the smallest y >= x such that y % a == 0.
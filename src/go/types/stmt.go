@@ -102,6 +102,14 @@ const (
 	finalSwitchCase
 )
 
+// intCondition converts x to bool if x is the untyped integer
+// constant 0 or 1 and Config.AllowIntAsBool is set.
+func (check *Checker) intCondition(x *operand) {
+	if check.conf.AllowIntAsBool && intAsBool(x) != nil {
+		check.convertUntyped(x, Typ[Bool])
+	}
+}
+
 func (check *Checker) simpleStmt(s ast.Stmt) {
 	if s != nil {
 		check.stmt(0, s)
@@ -252,7 +260,7 @@ L:
 		}
 		// Order matters: By comparing v against x, error positions are at the case values.
 		res := v // keep original v unchanged
-		check.comparison(&res, x, token.EQL)
+		check.comparison(&res, x, token.EQL, nil)
 		if res.mode == invalid {
 			continue L
 		}
@@ -513,6 +521,7 @@ func (check *Checker) stmt(ctxt stmtContext, s ast.Stmt) {
 		check.simpleStmt(s.Init)
 		var x operand
 		check.expr(&x, s.Cond)
+		check.intCondition(&x)
 		if x.mode != invalid && !isBoolean(x.typ) {
 			check.error(s.Cond, _InvalidCond, "non-boolean condition in if statement")
 		}
@@ -746,6 +755,7 @@ func (check *Checker) stmt(ctxt stmtContext, s ast.Stmt) {
 		if s.Cond != nil {
 			var x operand
 			check.expr(&x, s.Cond)
+			check.intCondition(&x)
 			if x.mode != invalid && !isBoolean(x.typ) {
 				check.error(s.Cond, _InvalidCond, "non-boolean condition in for statement")
 			}
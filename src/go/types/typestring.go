@@ -70,6 +70,22 @@ func TypeString(typ Type, qf Qualifier) string {
 	return buf.String()
 }
 
+// TypeStringForErrors returns the string representation of typ in the
+// form used by the type checker's error messages. It differs from
+// TypeString in that internal annotations (such as those of instantiated
+// types) are removed, and in that a nil Qualifier qualifies package-level
+// objects by their package name rather than by their import path.
+//
+// The type checker does not qualify objects of the package being checked.
+// To reproduce its messages exactly, provide a Qualifier that returns the
+// empty string for that package and the package name otherwise.
+func TypeStringForErrors(typ Type, qf Qualifier) string {
+	if qf == nil {
+		qf = (*Package).Name
+	}
+	return stripAnnotations(TypeString(typ, qf))
+}
+
 // WriteType writes the string representation of typ to buf.
 // The Qualifier controls the printing of
 // package-level objects, and may be nil.
@@ -223,3 +223,38 @@ func TestQualifiedTypeString(t *testing.T) {
 		}
 	}
 }
+
+func TestTypeStringForErrors(t *testing.T) {
+	p := NewPackage("example.com/p", "p")
+	q := NewPackage("example.com/q", "q")
+	pT := NewNamed(NewTypeName(token.NoPos, p, "T", nil), Typ[Int], nil)
+	qS := NewNamed(NewTypeName(token.NoPos, q, "S", nil), new(Struct), nil)
+	errorType := Universe.Lookup("error").Type()
+
+	relativeTo := func(this *Package) Qualifier {
+		return func(pkg *Package) string {
+			if pkg != this {
+				return pkg.Name()
+			}
+			return ""
+		}
+	}
+
+	for _, test := range []struct {
+		typ  Type
+		qf   Qualifier
+		want string
+	}{
+		{Typ[UntypedInt], nil, "untyped int"},
+		{NewSlice(Universe.Lookup("byte").Type()), nil, "[]byte"},
+		{pT, nil, "p.T"},
+		{pT, relativeTo(p), "T"},
+		{NewMap(Typ[String], pT), relativeTo(q), "map[string]p.T"},
+		{NewPointer(qS), relativeTo(p), "*q.S"},
+		{NewSignature(nil, NewTuple(NewVar(token.NoPos, nil, "", Typ[Int])), NewTuple(NewVar(token.NoPos, nil, "", errorType)), false), nil, "func(int) error"},
+	} {
+		if got := TypeStringForErrors(test.typ, test.qf); got != test.want {
+			t.Errorf("TypeStringForErrors(%s) = %s, want %s", test.typ, got, test.want)
+		}
+	}
+}
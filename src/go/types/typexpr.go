@@ -38,6 +38,10 @@ func (check *Checker) ident(x *operand, e *ast.Ident, def *Named, wantType bool)
 		return
 	}
 	check.recordUse(e, obj)
+	if !wantType {
+		// e is used in an expression
+		check.recordShadowedUniverse(e, obj)
+	}
 
 	// Type-check the object.
 	// Only call Checker.objDecl if the object doesn't have a type yet
@@ -85,6 +89,7 @@ func (check *Checker) ident(x *operand, e *ast.Ident, def *Named, wantType bool)
 		x.mode = constant_
 
 	case *TypeName:
+		check.typeName = obj
 		x.mode = typexpr
 
 	case *Var:
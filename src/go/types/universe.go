@@ -281,3 +281,19 @@ func def(obj Object) {
 		panic("internal error: double declaration")
 	}
 }
+
+// predeclaredKind describes the kind of the predeclared object obj.
+func predeclaredKind(obj Object) string {
+	switch obj.(type) {
+	case *TypeName:
+		return "type"
+	case *Const:
+		return "constant"
+	case *Nil:
+		return "nil"
+	case *Builtin:
+		return "builtin function"
+	}
+	unreachable()
+	return ""
+}